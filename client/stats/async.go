@@ -0,0 +1,149 @@
+package stats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/titpetric/microservice/internal"
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+// Async is a stats.StatsService sending Push requests in the background
+//
+// Requests are buffered in a bounded queue and sent by a pool of workers
+// in parallel. When the queue is full, requests are dropped. Close sends
+// the remaining requests before returning. Background requests are
+// traced as spans continuing the trace of the Push caller.
+//
+// To use it through wire, provide it in place of New:
+//
+//	func NewAsyncClient(client *http.Client, discovery internal.Discovery) stats.StatsService {
+//		return NewAsync(New(client, discovery))
+//	}
+type Async struct {
+	sync.RWMutex
+
+	client stats.StatsService
+	closed bool
+	queue  chan asyncRequest
+	wg     sync.WaitGroup
+
+	queueSize int
+	workers   int
+	timeout   time.Duration
+	dropped   func(*stats.PushRequest, error)
+}
+
+// asyncRequest holds a queued request with the trace of the caller
+type asyncRequest struct {
+	*stats.PushRequest
+	ctx context.Context
+}
+
+// AsyncOption modifies an *Async
+type AsyncOption func(*Async)
+
+// WithQueueSize limits the number of buffered requests
+func WithQueueSize(size int) AsyncOption {
+	return func(a *Async) {
+		a.queueSize = size
+	}
+}
+
+// WithWorkers sets the number of requests sent in parallel
+func WithWorkers(workers int) AsyncOption {
+	return func(a *Async) {
+		a.workers = workers
+	}
+}
+
+// WithPushTimeout limits the duration of each background request
+func WithPushTimeout(timeout time.Duration) AsyncOption {
+	return func(a *Async) {
+		a.timeout = timeout
+	}
+}
+
+// WithDropHandler is called for requests which are dropped or fail
+func WithDropHandler(dropped func(*stats.PushRequest, error)) AsyncOption {
+	return func(a *Async) {
+		a.dropped = dropped
+	}
+}
+
+// NewAsync creates an *Async sending requests with client
+func NewAsync(client stats.StatsService, options ...AsyncOption) *Async {
+	a := &Async{
+		client:    client,
+		queueSize: 1024,
+		workers:   4,
+		timeout:   5 * time.Second,
+		dropped:   func(*stats.PushRequest, error) {},
+	}
+	for _, option := range options {
+		option(a)
+	}
+
+	a.queue = make(chan asyncRequest, a.queueSize)
+	for i := 0; i < a.workers; i++ {
+		a.wg.Add(1)
+		go a.run()
+	}
+	return a
+}
+
+// Push queues a request, returning without waiting for the response
+func (a *Async) Push(ctx context.Context, r *stats.PushRequest) (*stats.PushResponse, error) {
+	a.RLock()
+	defer a.RUnlock()
+
+	if a.closed {
+		err := twirp.NewError(twirp.Unavailable, "client is closed")
+		a.dropped(r, err)
+		return nil, err
+	}
+
+	request := asyncRequest{
+		PushRequest: r,
+		ctx:         internal.GetTracer().Detach(ctx),
+	}
+
+	select {
+	case a.queue <- request:
+	default:
+		a.dropped(r, twirp.NewError(twirp.ResourceExhausted, "client queue is full"))
+	}
+	return new(stats.PushResponse), nil
+}
+
+// Close sends the queued requests and stops the workers
+func (a *Async) Close() error {
+	a.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.Unlock()
+
+	a.wg.Wait()
+	return nil
+}
+
+func (a *Async) run() {
+	defer a.wg.Done()
+
+	for r := range a.queue {
+		ctx, span := internal.GetTracer().StartSpan(r.ctx, "stats.Push", "async")
+		ctx, cancel := context.WithTimeout(ctx, a.timeout)
+		if _, err := a.client.Push(ctx, r.PushRequest); err != nil {
+			a.dropped(r.PushRequest, err)
+		}
+		cancel()
+		span.End()
+	}
+}
+
+var _ stats.StatsService = &Async{}
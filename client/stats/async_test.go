@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/atomic"
+
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+type testService struct {
+	pushed  *atomic.Int32
+	blocked chan struct{}
+}
+
+func (svc *testService) Push(ctx context.Context, r *stats.PushRequest) (*stats.PushResponse, error) {
+	<-svc.blocked
+	svc.pushed.Inc()
+	return new(stats.PushResponse), nil
+}
+
+func TestAsync(t *testing.T) {
+	svc := &testService{
+		pushed:  atomic.NewInt32(0),
+		blocked: make(chan struct{}),
+	}
+	dropped := atomic.NewInt32(0)
+	client := NewAsync(svc, WithQueueSize(4), WithWorkers(1), WithDropHandler(func(*stats.PushRequest, error) {
+		dropped.Inc()
+	}))
+
+	// one request is taken by the blocked worker, four are queued
+	for i := 0; i < 10; i++ {
+		if _, err := client.Push(context.Background(), &stats.PushRequest{Property: "news"}); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+	}
+	close(svc.blocked)
+	client.Close()
+
+	if pushed := svc.pushed.Load(); pushed+dropped.Load() != 10 || pushed < 4 || pushed > 5 {
+		t.Errorf("Unexpected pushed=%d dropped=%d", pushed, dropped.Load())
+	}
+	if _, err := client.Push(context.Background(), &stats.PushRequest{}); err == nil {
+		t.Errorf("Expected error on Push after Close")
+	}
+}
@@ -6,15 +6,28 @@ package stats
 import (
 	"net/http"
 
+	"github.com/titpetric/microservice/internal"
 	"github.com/titpetric/microservice/rpc/stats"
 )
 
 // New creates a Stats RPC client
-func New() stats.StatsService {
-	return NewCustom("http://stats.service:3000", &http.Client{})
+//
+// Endpoints are discovered with internal.NewResolver, falling back
+// to the default service address.
+func New(client *http.Client, discovery internal.Discovery) stats.StatsService {
+	return NewCustom("http://stats.service:3000", internal.WithHTTPClient(client), internal.WithDiscovery("stats", discovery))
 }
 
-// NewCustom creates a Stats RPC client with custom Address/HTTP Client
-func NewCustom(addr string, client stats.HTTPClient) stats.StatsService {
-	return stats.NewStatsServiceJSONClient(addr, client)
+// NewCustom creates a Stats RPC client with custom Address/client options
+//
+// The client uses protobuf encoding, unless internal.WithJSON is passed.
+// Failed requests are retried with exponential backoff, and a circuit
+// breaker stops requests to an unhealthy service.
+func NewCustom(addr string, options ...internal.ClientOption) stats.StatsService {
+	opts := internal.NewClientOptions(options...)
+	client := internal.NewClient(opts)
+	if opts.JSON {
+		return stats.NewStatsServiceJSONClient(addr, client)
+	}
+	return stats.NewStatsServiceProtobufClient(addr, client)
 }
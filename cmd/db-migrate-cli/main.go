@@ -6,6 +6,7 @@ import (
 
 	"github.com/SentimensRG/sigctx"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 
 	"github.com/titpetric/microservice/db"
 )
@@ -15,13 +16,33 @@ func main() {
 		db      db.ConnectionOptions
 		real    bool
 		service string
+
+		steps int
+		to    string
 	}
 	flag.StringVar(&config.db.Credentials.Driver, "db-driver", "mysql", "Database driver")
 	flag.StringVar(&config.db.Credentials.DSN, "db-dsn", "", "DSN for database connection")
 	flag.StringVar(&config.service, "service", "", "Service name for migrations")
 	flag.BoolVar(&config.real, "real", false, "false = print migrations, true = run migrations")
+	flag.Usage = func() {
+		log.Println("Usage: db-migrate-cli [flags] [up|rollback [rollback flags]]")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
+	command := flag.Arg(0)
+	switch command {
+	case "", "up":
+	case "rollback":
+		rollbackFlags := flag.NewFlagSet("rollback", flag.ExitOnError)
+		rollbackFlags.IntVar(&config.steps, "steps", 1, "Number of migrations to roll back")
+		rollbackFlags.StringVar(&config.to, "to", "", "Roll back migrations applied after this filename")
+		rollbackFlags.Parse(flag.Args()[1:])
+	default:
+		flag.Usage()
+		log.Fatalf("Unknown command: %s", command)
+	}
+
 	if config.service == "" {
 		log.Printf("Available migration services: %+v", db.List())
 		log.Fatal()
@@ -29,6 +50,25 @@ func main() {
 
 	ctx := sigctx.New()
 
+	if command == "rollback" {
+		if !config.real {
+			log.Fatal("Rollback changes the database, run with -real")
+		}
+		handle, err := db.ConnectWithRetry(ctx, config.db)
+		if err != nil {
+			log.Fatalf("Error connecting to database: %+v", err)
+		}
+		if config.to != "" {
+			err = db.RollbackTo(config.service, config.to, handle)
+		} else {
+			err = db.Rollback(config.service, config.steps, handle)
+		}
+		if err != nil {
+			log.Fatalf("An error occurred: %+v", err)
+		}
+		return
+	}
+
 	switch config.real {
 	case true:
 		handle, err := db.ConnectWithRetry(ctx, config.db)
@@ -39,7 +79,7 @@ func main() {
 			log.Fatalf("An error occurred: %+v", err)
 		}
 	default:
-		if err := db.Print(config.service); err != nil {
+		if err := db.Print(config.service, db.DialectFor(config.db.Credentials.Driver)); err != nil {
 			log.Fatalf("An error occurred: %+v", err)
 		}
 	}
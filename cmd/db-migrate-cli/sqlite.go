@@ -0,0 +1,8 @@
+// +build cgo
+
+package main
+
+import (
+	// go sqlite3 driver for -db-driver=sqlite3 (requires cgo)
+	_ "github.com/mattn/go-sqlite3"
+)
@@ -190,8 +190,8 @@ func renderGo(basePath string, service string, tables []*Table) error {
 		}
 		// Table name
 		fmt.Fprintf(buf, "// %sTable is the name of the table in the DB\n", tableName)
-		// Table is SQL backtick quoted so we can allow reserved words like `group`
-		fmt.Fprintf(buf, "const %sTable = \"`%s`\"\n", tableName, table.Name)
+		// Table is unquoted, queries quote it per dialect with db.Dialect.Quote
+		fmt.Fprintf(buf, "const %sTable = \"%s\"\n", tableName, table.Name)
 		// Table fields
 		fmt.Fprintf(buf, "// %sFields are all the field names in the DB table\n", tableName)
 		fmt.Fprintf(buf, "var %sFields = ", tableName)
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go/format"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/golang/protobuf/protoc-gen-go/generator"
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"github.com/pkg/errors"
+
+	"github.com/titpetric/microservice/proto/validate"
+)
+
+func generate(request *plugin.CodeGeneratorRequest) *plugin.CodeGeneratorResponse {
+	response := new(plugin.CodeGeneratorResponse)
+
+	params := parameters(request.GetParameter())
+	internal := params["internal"]
+	if internal == "" {
+		internal = "github.com/titpetric/microservice/internal"
+	}
+
+	files := map[string]*descriptor.FileDescriptorProto{}
+	for _, file := range request.ProtoFile {
+		files[file.GetName()] = file
+	}
+
+	for _, name := range request.FileToGenerate {
+		content, err := render(files[name], internal)
+		if err != nil {
+			response.Error = proto.String(fmt.Sprintf("%s: %s", name, err))
+			return response
+		}
+		response.File = append(response.File, &plugin.CodeGeneratorResponse_File{
+			Name:    proto.String(strings.TrimSuffix(name, ".proto") + ".validate.go"),
+			Content: proto.String(content),
+		})
+	}
+	return response
+}
+
+// goPackageName returns the go package name for a proto file
+func goPackageName(file *descriptor.FileDescriptorProto) string {
+	pkg := file.GetOptions().GetGoPackage()
+	if idx := strings.LastIndex(pkg, ";"); idx >= 0 {
+		return pkg[idx+1:]
+	}
+	if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+		return pkg[idx+1:]
+	}
+	if pkg != "" {
+		return pkg
+	}
+	return strings.Replace(file.GetPackage(), ".", "_", -1)
+}
+
+// renderer keeps state while rendering a single proto file
+type renderer struct {
+	file    *descriptor.FileDescriptorProto
+	imports map[string]bool
+
+	// validated holds the fully qualified names of messages with Validate()
+	validated map[string]bool
+
+	vars bytes.Buffer
+	body bytes.Buffer
+}
+
+func render(file *descriptor.FileDescriptorProto, internal string) (string, error) {
+	if file == nil {
+		return "", errors.New("file not found in request")
+	}
+	if file.GetSyntax() != "proto3" {
+		return "", errors.New("only proto3 files are supported")
+	}
+
+	r := &renderer{
+		file:      file,
+		imports:   map[string]bool{},
+		validated: map[string]bool{},
+	}
+
+	for _, message := range file.MessageType {
+		if err := r.message(message, nil); err != nil {
+			return "", err
+		}
+	}
+	for _, service := range file.Service {
+		r.service(service)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintln(&out, "// Code generated by protoc-gen-microservice-validate. DO NOT EDIT.")
+	fmt.Fprintln(&out, "// source:", file.GetName())
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "package", goPackageName(file))
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "import (")
+	for _, pkg := range []string{"context", "regexp"} {
+		if r.imports[pkg] {
+			fmt.Fprintf(&out, "\t%q\n", pkg)
+		}
+	}
+	if r.imports["internal"] {
+		fmt.Fprintln(&out)
+		fmt.Fprintf(&out, "\t%q\n", internal)
+	}
+	fmt.Fprintln(&out, ")")
+	fmt.Fprintln(&out)
+	out.Write(r.vars.Bytes())
+	out.Write(r.body.Bytes())
+
+	result, err := format.Source(out.Bytes())
+	if err != nil {
+		return "", errors.Wrap(err, "error formatting generated code")
+	}
+	return string(result), nil
+}
+
+func (r *renderer) message(message *descriptor.DescriptorProto, parent []string) error {
+	path := append(append([]string{}, parent...), message.GetName())
+	goName := generator.CamelCaseSlice(path)
+
+	for _, nested := range message.NestedType {
+		if nested.GetOptions().GetMapEntry() {
+			continue
+		}
+		if err := r.message(nested, path); err != nil {
+			return err
+		}
+	}
+
+	rules := []string{}
+	for _, field := range message.Field {
+		fieldRules, err := r.field(goName, field)
+		if err != nil {
+			return errors.Wrapf(err, "%s.%s", message.GetName(), field.GetName())
+		}
+		rules = append(rules, fieldRules...)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	r.imports["internal"] = true
+	r.validated["."+r.file.GetPackage()+"."+strings.Join(path, ".")] = true
+
+	fmt.Fprintf(&r.body, "// Validate checks %s field rules\n", goName)
+	fmt.Fprintf(&r.body, "func (m *%s) Validate() error {\n", goName)
+	fmt.Fprintf(&r.body, "\treturn internal.Validate(\n")
+	for _, rule := range rules {
+		fmt.Fprintf(&r.body, "\t\t%s,\n", rule)
+	}
+	fmt.Fprintf(&r.body, "\t)\n}\n\n")
+	return nil
+}
+
+func (r *renderer) field(message string, field *descriptor.FieldDescriptorProto) ([]string, error) {
+	options := field.GetOptions()
+	if options == nil {
+		return nil, nil
+	}
+
+	var (
+		required bool
+		min, max *float64
+		pattern  *string
+		in       []string
+	)
+	if val, err := proto.GetExtension(options, validate.E_Required); err == nil {
+		required = *val.(*bool)
+	}
+	if val, err := proto.GetExtension(options, validate.E_Min); err == nil {
+		min = val.(*float64)
+	}
+	if val, err := proto.GetExtension(options, validate.E_Max); err == nil {
+		max = val.(*float64)
+	}
+	if val, err := proto.GetExtension(options, validate.E_Pattern); err == nil {
+		pattern = val.(*string)
+	}
+	if val, err := proto.GetExtension(options, validate.E_In); err == nil {
+		in = val.([]string)
+	}
+
+	if !required && min == nil && max == nil && pattern == nil && len(in) == 0 {
+		return nil, nil
+	}
+	if field.OneofIndex != nil {
+		return nil, errors.New("rules on oneof fields are not supported")
+	}
+
+	name := field.GetName()
+	value := "m." + generator.CamelCase(name)
+	quoted := strconv.Quote(name)
+
+	result := []string{}
+
+	// repeated fields only support required
+	if field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		if min != nil || max != nil || pattern != nil || len(in) > 0 {
+			return nil, errors.New("repeated fields only support the required rule")
+		}
+		return append(result, fmt.Sprintf("internal.Required(%s, len(%s) > 0)", quoted, value)), nil
+	}
+
+	switch field.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		if min != nil || max != nil {
+			return nil, errors.New("string fields don't support min/max rules")
+		}
+		if required {
+			result = append(result, fmt.Sprintf("internal.Required(%s, %s != \"\")", quoted, value))
+		}
+		if pattern != nil {
+			r.imports["regexp"] = true
+			patternVar := fmt.Sprintf("_%s_%s_pattern", message, generator.CamelCase(name))
+			fmt.Fprintf(&r.vars, "var %s = regexp.MustCompile(%s)\n\n", patternVar, strconv.Quote(*pattern))
+			result = append(result, fmt.Sprintf("internal.Invalid(%s, %s.MatchString(%s), %s)", quoted, patternVar, value, strconv.Quote("must match "+*pattern)))
+		}
+		if len(in) > 0 {
+			allowed := make([]string, len(in))
+			for k, v := range in {
+				allowed[k] = strconv.Quote(v)
+			}
+			result = append(result, fmt.Sprintf("internal.OneOf(%s, %s, %s)", quoted, value, strings.Join(allowed, ", ")))
+		}
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		if min != nil || max != nil || pattern != nil || len(in) > 0 {
+			return nil, errors.New("bytes fields only support the required rule")
+		}
+		result = append(result, fmt.Sprintf("internal.Required(%s, len(%s) > 0)", quoted, value))
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if min != nil || max != nil || pattern != nil || len(in) > 0 {
+			return nil, errors.New("message fields only support the required rule")
+		}
+		result = append(result, fmt.Sprintf("internal.Required(%s, %s != nil)", quoted, value))
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return nil, errors.New("bool fields don't support rules")
+	default:
+		if pattern != nil || len(in) > 0 {
+			return nil, errors.New("numeric fields don't support pattern/in rules")
+		}
+		isFloat := field.GetType() == descriptor.FieldDescriptorProto_TYPE_DOUBLE || field.GetType() == descriptor.FieldDescriptorProto_TYPE_FLOAT
+		isUnsigned := false
+		switch field.GetType() {
+		case descriptor.FieldDescriptorProto_TYPE_UINT32, descriptor.FieldDescriptorProto_TYPE_UINT64,
+			descriptor.FieldDescriptorProto_TYPE_FIXED32, descriptor.FieldDescriptorProto_TYPE_FIXED64:
+			isUnsigned = true
+		}
+		number := func(val float64) (string, error) {
+			if !isFloat && val != float64(int64(val)) {
+				return "", errors.Errorf("integer field has a fractional bound: %v", val)
+			}
+			// the generated comparison wouldn't compile for unsigned fields
+			if isUnsigned && val < 0 {
+				return "", errors.Errorf("unsigned field has a negative bound: %v", val)
+			}
+			return strconv.FormatFloat(val, 'g', -1, 64), nil
+		}
+		if required {
+			result = append(result, fmt.Sprintf("internal.Required(%s, %s != 0)", quoted, value))
+		}
+		if min != nil {
+			bound, err := number(*min)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, fmt.Sprintf("internal.Invalid(%s, %s >= %s, %s)", quoted, value, bound, strconv.Quote("must be at least "+bound)))
+		}
+		if max != nil {
+			bound, err := number(*max)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, fmt.Sprintf("internal.Invalid(%s, %s <= %s, %s)", quoted, value, bound, strconv.Quote("must be at most "+bound)))
+		}
+	}
+	return result, nil
+}
+
+func (r *renderer) service(service *descriptor.ServiceDescriptorProto) {
+	name := generator.CamelCase(service.GetName())
+	private := strings.ToLower(name[:1]) + name[1:] + "Validator"
+
+	r.imports["context"] = true
+
+	fmt.Fprintf(&r.body, "// New%sValidator wraps %s, validating requests before they are handled\n", name, name)
+	fmt.Fprintf(&r.body, "func New%sValidator(svc %s) %s {\n", name, name, name)
+	fmt.Fprintf(&r.body, "\treturn &%s{svc}\n}\n\n", private)
+	fmt.Fprintf(&r.body, "type %s struct {\n\t%s\n}\n\n", private, name)
+
+	for _, method := range service.Method {
+		input := r.typeName(method.GetInputType())
+		output := r.typeName(method.GetOutputType())
+		methodName := generator.CamelCase(method.GetName())
+
+		fmt.Fprintf(&r.body, "func (v *%s) %s(ctx context.Context, req *%s) (*%s, error) {\n", private, methodName, input, output)
+		if r.validated[method.GetInputType()] {
+			fmt.Fprintf(&r.body, "\tif err := req.Validate(); err != nil {\n\t\treturn nil, err\n\t}\n")
+		}
+		fmt.Fprintf(&r.body, "\treturn v.%s.%s(ctx, req)\n}\n\n", name, methodName)
+	}
+	fmt.Fprintf(&r.body, "var _ %s = &%s{}\n\n", name, private)
+}
+
+// typeName returns the go type name for a message in the current package
+func (r *renderer) typeName(name string) string {
+	name = strings.TrimPrefix(name, "."+r.file.GetPackage()+".")
+	return generator.CamelCaseSlice(strings.Split(name, "."))
+}
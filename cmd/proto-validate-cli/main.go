@@ -0,0 +1,55 @@
+package main
+
+// protoc plugin generating Validate() methods from field options
+// declared in proto/validate/validate.proto, used as:
+//
+//	protoc --plugin=protoc-gen-microservice-validate=build/protoc-gen-microservice-validate \
+//		--microservice-validate_out=internal=${MODULE}/internal,paths=source_relative:. rpc/stats/stats.proto
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+)
+
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatalf("Error reading input: %+v", err)
+	}
+
+	request := new(plugin.CodeGeneratorRequest)
+	if err := proto.Unmarshal(input, request); err != nil {
+		log.Fatalf("Error parsing input: %+v", err)
+	}
+
+	response := generate(request)
+
+	output, err := proto.Marshal(response)
+	if err != nil {
+		log.Fatalf("Error encoding output: %+v", err)
+	}
+	if _, err := os.Stdout.Write(output); err != nil {
+		log.Fatalf("Error writing output: %+v", err)
+	}
+}
+
+// parameters parses `key=value,key=value` plugin parameters
+func parameters(parameter string) map[string]string {
+	result := map[string]string{}
+	for _, param := range strings.Split(parameter, ",") {
+		if param == "" {
+			continue
+		}
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 1 {
+			kv = append(kv, "")
+		}
+		result[kv[0]] = kv[1]
+	}
+	return result
+}
@@ -4,57 +4,156 @@ package main
 // generator and template: templates/cmd_main.go.tpl
 
 import (
-	"log"
+	"context"
+	"os"
 
 	"net/http"
 
 	"github.com/SentimensRG/sigctx"
 	"github.com/namsral/flag"
 
+	"github.com/titpetric/microservice/config"
 	"github.com/titpetric/microservice/db"
+	"github.com/titpetric/microservice/inject"
 	"github.com/titpetric/microservice/internal"
 	"github.com/titpetric/microservice/rpc/stats"
 	server "github.com/titpetric/microservice/server/stats"
 )
 
 func main() {
-	var config struct {
-		migrate   bool
-		migrateDB db.ConnectionOptions
+	log := internal.NewLogger(internal.LogOptions{})
+
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		log.Error("Error loading config", "err", err)
+		os.Exit(2)
+	}
+	if cfg.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Error("Error printing config", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+	log = inject.Logger(cfg.Log)
+
+	// the tracer is set before database connections and clients are created
+	tracer, err := inject.Tracer(cfg.Tracing)
+	if err != nil {
+		log.Error("Error in inject.Tracer()", "err", err)
+		os.Exit(1)
 	}
-	flag.StringVar(&config.migrateDB.Credentials.Driver, "migrate-db-driver", "mysql", "Migrations: Database driver")
-	flag.StringVar(&config.migrateDB.Credentials.DSN, "migrate-db-dsn", "", "Migrations: DSN for database connection")
-	flag.BoolVar(&config.migrate, "migrate", false, "Run migrations?")
-	flag.Parse()
+	internal.SetTracer(tracer)
 
 	ctx := sigctx.New()
 
-	if config.migrate {
-		handle, err := db.ConnectWithRetry(ctx, config.migrateDB)
+	if cfg.Migrate.Enabled {
+		handle, err := db.ConnectWithRetry(ctx, db.ConnectionOptions{Credentials: cfg.Migrate.Database})
 		if err != nil {
-			log.Fatalf("Error connecting to database: %+v", err)
+			log.Error("Error connecting to database", "err", err)
+			os.Exit(1)
 		}
 		if err := db.Run("stats", handle); err != nil {
-			log.Fatalf("An error occurred: %+v", err)
+			log.Error("An error occurred", "err", err)
+			os.Exit(1)
 		}
 	}
 
-	srv, err := server.New(ctx)
+	// the service is cancelled after the HTTP server stops serving requests,
+	// or on a signal while it's starting up (e.g. database connection retries)
+	serviceCtx, serviceCancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			serviceCancel()
+		case <-started:
+		}
+	}()
+
+	srv, err := server.New(serviceCtx, cfg)
+	close(started)
+	if err != nil {
+		log.Error("Error in service.New()", "err", err)
+		os.Exit(1)
+	}
+
+	service := stats.NewStatsServiceValidator(srv)
+
+	twirpHandler := stats.NewStatsServiceServer(service, internal.NewServerHooks())
+
+	auth, err := inject.Authenticator(server.AuthRules, cfg.Auth)
 	if err != nil {
-		log.Fatalf("Error in service.New(): %+v", err)
+		log.Error("Error in inject.Authenticator()", "err", err)
+		os.Exit(1)
 	}
 
-	twirpHandler := stats.NewStatsServiceServer(srv, internal.NewServerHooks())
+	grpcServer := internal.NewGRPCServer()
+	stats.RegisterStatsServiceServer(grpcServer, service)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", internal.WrapGRPC(grpcServer, internal.WrapGRPCWeb(grpcServer, twirpHandler)))
+	server.RegisterHandlers(mux, service, log)
+
+	// gRPC and Twirp share the port and middleware, h2c allows HTTP/2 without TLS
+	drainer := internal.NewDrainer()
+	httpServer := &http.Server{
+		Addr: cfg.Server.Addr,
+	}
+	httpServer.Handler, err = internal.WrapH2C(httpServer, drainer.Wrap(internal.WrapAll(auth.Wrap(mux), internal.CORSOrigins(cfg.CORS.Origins))))
+	if err != nil {
+		log.Error("Error in internal.WrapH2C()", "err", err)
+		os.Exit(1)
+	}
 
-	log.Println("Starting service on port :3000")
+	log.Info("Starting service (Twirp, gRPC)", "addr", cfg.Server.Addr)
 	go func() {
-		err := http.ListenAndServe(":3000", internal.WrapAll(twirpHandler))
+		err := httpServer.ListenAndServe()
 		if err != http.ErrServerClosed {
-			log.Println("Server error:", err)
+			log.Error("Server error", "err", err)
 		}
 	}()
 	<-ctx.Done()
 
-	srv.Shutdown()
-	log.Println("Done.")
+	// stop accepting connections and wait for in-flight requests
+	log.Info("Shutting down HTTP server")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Error("Error shutting down HTTP server", "err", err)
+	}
+	// h2c connections are hijacked, wait for their requests separately;
+	// gRPC is served with ServeHTTP, which doesn't support GracefulStop
+	if err := drainer.Wait(shutdownCtx); err != nil {
+		log.Error("Error waiting for in-flight requests", "err", err)
+	}
+	grpcServer.Stop()
+	shutdownCancel()
+
+	// flush pending data and close the database, a slow HTTP drain
+	// doesn't take time away from the final flush
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), cfg.Server.FlushTimeout)
+	defer flushCancel()
+
+	done := make(chan struct{})
+	go func() {
+		log.Info("Shutting down service")
+		serviceCancel()
+		srv.Shutdown()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info("Done.")
+	case <-flushCtx.Done():
+		log.Warn("Shutdown timed out.")
+	}
+
+	// traces are flushed within the remaining flush timeout
+	if err := tracer.Close(flushCtx); err != nil {
+		log.Error("Error flushing traces", "err", err)
+	}
 }
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/titpetric/microservice/db"
+)
+
+type (
+	// Config is the service configuration
+	//
+	// Values are loaded with increasing precedence from defaults, the
+	// YAML or TOML config file, environment variables and flags.
+	Config struct {
+		// File is the YAML or TOML config file, set with -config-file
+		File string `yaml:"-" toml:"-"`
+		// PrintConfig prints the effective config and exits
+		PrintConfig bool `yaml:"-" toml:"-"`
+
+		Server    Server         `yaml:"server" toml:"server"`
+		Database  db.Credentials `yaml:"database" toml:"database"`
+		Migrate   Migrate        `yaml:"migrate" toml:"migrate"`
+		Flusher   Flusher        `yaml:"flusher" toml:"flusher"`
+		CORS      CORS           `yaml:"cors" toml:"cors"`
+		Log       Log            `yaml:"log" toml:"log"`
+		Tracing   Tracing        `yaml:"tracing" toml:"tracing"`
+		Auth      Auth           `yaml:"auth" toml:"auth"`
+		Ingest    Ingest         `yaml:"ingest" toml:"ingest"`
+		Discovery Discovery      `yaml:"discovery" toml:"discovery"`
+	}
+
+	// Server configures the HTTP server and instance
+	Server struct {
+		// Addr is the HTTP listen address
+		Addr string `yaml:"addr" toml:"addr"`
+		// ID is the sonyflake machine ID, 0 uses the private IP
+		ID uint `yaml:"id" toml:"id"`
+		// ShutdownTimeout is the timeout for draining HTTP requests on shutdown
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+		// FlushTimeout is the timeout for the service shutdown, flushing pending data
+		FlushTimeout time.Duration `yaml:"flush_timeout" toml:"flush_timeout"`
+	}
+
+	// Migrate configures database migrations on startup
+	Migrate struct {
+		Enabled bool `yaml:"enabled" toml:"enabled"`
+		// Database defaults to the service database
+		Database db.Credentials `yaml:"database" toml:"database"`
+	}
+
+	// Flusher configures the background data flush job
+	Flusher struct {
+		// Interval is the time between flushes
+		Interval time.Duration `yaml:"interval" toml:"interval"`
+		// Workers is the number of queues flushed concurrently
+		Workers int `yaml:"workers" toml:"workers"`
+		// Sink is a comma separated list of `db`, `db-bulk`, `jsonl` and `stdout`
+		Sink string `yaml:"sink" toml:"sink"`
+		// SinkPath is the output folder for the jsonl sink
+		SinkPath string `yaml:"sink_path" toml:"sink_path"`
+	}
+
+	// CORS configures allowed browser origins
+	CORS struct {
+		// Origins are allowed origins per property, `*` is the default property
+		//
+		// Without origins, all origins are allowed. Set with CORS_ORIGINS as
+		// JSON, e.g. `{"news": ["https://news.example.com"]}`.
+		Origins map[string][]string `yaml:"origins" toml:"origins"`
+	}
+
+	// Log configures the structured logger
+	Log struct {
+		// Level is the minimum level, `debug`, `info`, `warn` or `error`
+		Level string `yaml:"level" toml:"level"`
+		// Format is `json` or `logfmt`
+		Format string `yaml:"format" toml:"format"`
+	}
+
+	// Tracing configures the tracing backend
+	Tracing struct {
+		// Tracer is `elastic`, `otel` or `none`
+		Tracer string `yaml:"tracer" toml:"tracer"`
+		// Exporter is the OpenTelemetry exporter, `otlp` or `console`
+		Exporter string `yaml:"exporter" toml:"exporter"`
+	}
+
+	// Auth configures JWT verification and RPC authorization
+	Auth struct {
+		// RulesFile is a JSON file with rules, added to the service rules
+		RulesFile string `yaml:"rules_file" toml:"rules_file"`
+		// JWKSFiles are JWKS files with token verification keys
+		JWKSFiles []string `yaml:"jwks_files" toml:"jwks_files"`
+		// Issuer requires a matching `iss` claim
+		Issuer string `yaml:"issuer" toml:"issuer"`
+		// Audience requires a matching `aud` claim
+		Audience string `yaml:"audience" toml:"audience"`
+	}
+
+	// Ingest configures ingestion keys
+	Ingest struct {
+		// KeysRequired rejects pushes for properties without keys
+		KeysRequired bool `yaml:"keys_required" toml:"keys_required"`
+	}
+
+	// Discovery configures RPC client endpoints, per service name
+	//
+	// Endpoints are read from Addr, the services config File, or SRV,
+	// in that order; services missing from the File fall back to SRV. Addr and SRV can be set with `<SERVICE>_SERVICE_ADDR`
+	// (comma separated) and `<SERVICE>_SERVICE_SRV` environment variables.
+	Discovery struct {
+		// Addr are endpoints, e.g. `http://10.0.0.1:3000`
+		Addr map[string][]string `yaml:"addr" toml:"addr"`
+		// File is a JSON file, e.g. `{"stats": ["http://..."]}`
+		File string `yaml:"file" toml:"file"`
+		// SRV are DNS SRV record names, e.g. `_stats._tcp.service`
+		SRV map[string]string `yaml:"srv" toml:"srv"`
+	}
+)
+
+// Default returns a *Config with default values
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Addr:            ":3000",
+			ShutdownTimeout: 30 * time.Second,
+			FlushTimeout:    30 * time.Second,
+		},
+		Database: db.Credentials{
+			Driver: "mysql",
+		},
+		Flusher: Flusher{
+			Interval: 5 * time.Second,
+			Workers:  4,
+			Sink:     "db",
+			SinkPath: "data",
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: Tracing{
+			Tracer:   "elastic",
+			Exporter: "otlp",
+		},
+	}
+}
+
+// Validate checks the configuration values
+func (c *Config) Validate() error {
+	if c.Server.Addr == "" {
+		return errors.New("server.addr is required")
+	}
+	if c.Server.ID > 0xffff {
+		return errors.Errorf("server.id must be between 0 and 65535, got %d", c.Server.ID)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		return errors.Errorf("server.shutdown_timeout must be positive, got %s", c.Server.ShutdownTimeout)
+	}
+	if c.Server.FlushTimeout <= 0 {
+		return errors.Errorf("server.flush_timeout must be positive, got %s", c.Server.FlushTimeout)
+	}
+	if err := validateCredentials("database", c.Database); err != nil {
+		return err
+	}
+	if c.Migrate.Enabled {
+		if err := validateCredentials("migrate.database", c.Migrate.Database); err != nil {
+			return err
+		}
+	}
+	if c.Flusher.Interval <= 0 {
+		return errors.Errorf("flusher.interval must be positive, got %s", c.Flusher.Interval)
+	}
+	if c.Flusher.Workers < 1 {
+		return errors.Errorf("flusher.workers must be at least 1, got %d", c.Flusher.Workers)
+	}
+	if c.Flusher.Sink == "" {
+		return errors.New("flusher.sink is required")
+	}
+	if err := validateOneOf("log.format", c.Log.Format, "json", "logfmt", "text"); err != nil {
+		return err
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		return errors.Errorf("log.level is not supported: '%s'", c.Log.Level)
+	}
+	if err := validateOneOf("tracing.tracer", c.Tracing.Tracer, "elastic", "otel", "none"); err != nil {
+		return err
+	}
+	if err := validateOneOf("tracing.exporter", c.Tracing.Exporter, "otlp", "console", "stdout"); err != nil {
+		return err
+	}
+	if err := validateFile("auth.rules_file", c.Auth.RulesFile); err != nil {
+		return err
+	}
+	for _, filename := range c.Auth.JWKSFiles {
+		if err := validateFile("auth.jwks_files", filename); err != nil {
+			return err
+		}
+	}
+	if err := validateFile("discovery.file", c.Discovery.File); err != nil {
+		return err
+	}
+	for service, addrs := range c.Discovery.Addr {
+		for _, addr := range addrs {
+			if addr == "*" || !validOrigin(addr) {
+				return errors.Errorf("discovery.addr.%s has an invalid endpoint: '%s'", service, addr)
+			}
+		}
+	}
+	for property, origins := range c.CORS.Origins {
+		for _, origin := range origins {
+			if !validOrigin(origin) {
+				return errors.Errorf("cors.origins.%s has an invalid origin: '%s'", property, origin)
+			}
+		}
+	}
+	return nil
+}
+
+func validateOneOf(name, value string, allowed ...string) error {
+	for _, option := range allowed {
+		if value == option {
+			return nil
+		}
+	}
+	return errors.Errorf("%s is not supported: '%s'", name, value)
+}
+
+// validateFile checks that filename, if set, is a readable file
+func validateFile(name, filename string) error {
+	if filename == "" {
+		return nil
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return errors.Wrapf(err, "%s is not readable", name)
+	}
+	if info.IsDir() {
+		return errors.Errorf("%s is a directory: '%s'", name, filename)
+	}
+	return nil
+}
+
+// validOrigin accepts `*`, or a scheme and host without a path
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	idx := strings.Index(origin, "://")
+	if idx <= 0 || idx+3 == len(origin) {
+		return false
+	}
+	return !strings.ContainsAny(origin[idx+3:], "/?#")
+}
+
+func validateCredentials(name string, credentials db.Credentials) error {
+	switch credentials.Driver {
+	case "mysql", "postgres", "pgx", "sqlite", "sqlite3":
+	default:
+		return errors.Errorf("%s.driver is not supported: '%s'", name, credentials.Driver)
+	}
+	if credentials.DSN == "" {
+		return errors.Errorf("%s.dsn is required", name)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/titpetric/microservice/config"
+)
+
+func writeConfigFile(t *testing.T, name, contents string) string {
+	filename := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(filename, []byte(contents), 0644); err != nil {
+		t.Fatalf("Unexpected error writing config: %+v", err)
+	}
+	return filename
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	filename := writeConfigFile(t, "config.yml", `
+server:
+  addr: ":8080"
+  id: 3
+database:
+  driver: postgres
+  dsn: "user=stats password=secret host=db"
+flusher:
+  interval: 10s
+  workers: 8
+`)
+
+	t.Setenv("CONFIG_FILE", filename)
+	t.Setenv("FLUSHER_WORKERS", "16")
+	t.Setenv("CORS_ORIGINS", `{"news": ["https://news.example.com"]}`)
+	cfg, err := config.Load("test", []string{"-http-addr", ":9090"})
+	if err != nil {
+		t.Fatalf("Unexpected error loading config: %+v", err)
+	}
+
+	if cfg.Server.Addr != ":9090" {
+		t.Errorf("Expected flag to override file, got addr %q", cfg.Server.Addr)
+	}
+	if cfg.Flusher.Workers != 16 {
+		t.Errorf("Expected env to override file, got workers %d", cfg.Flusher.Workers)
+	}
+	if cfg.Server.ID != 3 || cfg.Database.Driver != "postgres" || cfg.Flusher.Interval != 10*time.Second {
+		t.Errorf("Expected values from file, got %+v", cfg)
+	}
+	if cfg.Server.ShutdownTimeout != 30*time.Second || cfg.Flusher.Sink != "db" {
+		t.Errorf("Expected defaults, got %+v", cfg)
+	}
+	if origins := cfg.CORS.Origins["news"]; len(cfg.CORS.Origins) != 1 || len(origins) != 1 || origins[0] != "https://news.example.com" {
+		t.Errorf("Expected CORS origins from env, got %+v", cfg.CORS.Origins)
+	}
+	if cfg.Migrate.Database != cfg.Database {
+		t.Errorf("Expected migrations to default to the database, got %+v", cfg.Migrate.Database)
+	}
+
+	var output bytes.Buffer
+	if err := cfg.Print(&output); err != nil {
+		t.Fatalf("Unexpected error printing config: %+v", err)
+	}
+	if strings.Contains(output.String(), "secret") || !strings.Contains(output.String(), "password=****") {
+		t.Errorf("Expected masked DSN in printed config, got:\n%s", output.String())
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	jwks := writeConfigFile(t, "jwks.json", `{"keys": []}`)
+	filename := writeConfigFile(t, "config.yml", `
+database:
+  dsn: "stats:stats@tcp(db:3306)/stats"
+discovery:
+  addr:
+    other_service: ["http://c:3000"]
+`)
+
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_FORMAT", "logfmt")
+	t.Setenv("TRACING", "otel")
+	t.Setenv("OTEL_TRACES_EXPORTER", "console")
+	t.Setenv("JWT_JWKS_FILES", jwks+", ")
+	t.Setenv("JWT_ISSUER", "issuer")
+	t.Setenv("INGEST_KEYS_REQUIRED", "true")
+	t.Setenv("STATS_SERVICE_ADDR", "http://a:3000, http://b:3000")
+	t.Setenv("STATS_SERVICE_SRV", "_stats._tcp.service")
+	cfg, err := config.Load("test", []string{"-config-file", filename})
+	if err != nil {
+		t.Fatalf("Unexpected error loading config: %+v", err)
+	}
+
+	if cfg.Log.Level != "debug" || cfg.Log.Format != "logfmt" {
+		t.Errorf("Expected log config from env, got %+v", cfg.Log)
+	}
+	if cfg.Tracing.Tracer != "otel" || cfg.Tracing.Exporter != "console" {
+		t.Errorf("Expected tracing config from env, got %+v", cfg.Tracing)
+	}
+	if len(cfg.Auth.JWKSFiles) != 1 || cfg.Auth.JWKSFiles[0] != jwks || cfg.Auth.Issuer != "issuer" {
+		t.Errorf("Expected auth config from env, got %+v", cfg.Auth)
+	}
+	if !cfg.Ingest.KeysRequired {
+		t.Errorf("Expected ingest config from env, got %+v", cfg.Ingest)
+	}
+	if addr := cfg.Discovery.Addr["stats"]; len(addr) != 2 || addr[1] != "http://b:3000" {
+		t.Errorf("Expected discovery addr from env, got %+v", cfg.Discovery.Addr)
+	}
+	if addr := cfg.Discovery.Addr["other-service"]; len(addr) != 1 {
+		t.Errorf("Expected discovery addr from file, got %+v", cfg.Discovery.Addr)
+	}
+	if cfg.Discovery.SRV["stats"] != "_stats._tcp.service" {
+		t.Errorf("Expected discovery srv from env, got %+v", cfg.Discovery.SRV)
+	}
+
+	var output bytes.Buffer
+	if err := cfg.Print(&output); err != nil {
+		t.Fatalf("Unexpected error printing config: %+v", err)
+	}
+	for _, section := range []string{"log:", "tracing:", "auth:", "ingest:", "discovery:"} {
+		if !strings.Contains(output.String(), "\n"+section+"\n") {
+			t.Errorf("Expected %s in printed config, got:\n%s", section, output.String())
+		}
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	filename := writeConfigFile(t, "config.toml", `
+[database]
+dsn = "stats:stats@tcp(db:3306)/stats"
+
+[flusher]
+sink = "db,jsonl"
+interval = "1s"
+`)
+
+	cfg, err := config.Load("test", []string{"-config-file", filename})
+	if err != nil {
+		t.Fatalf("Unexpected error loading config: %+v", err)
+	}
+	if cfg.Database.DSN != "stats:stats@tcp(db:3306)/stats" || cfg.Flusher.Sink != "db,jsonl" || cfg.Flusher.Interval != time.Second {
+		t.Errorf("Expected values from file, got %+v", cfg)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := map[string][]string{
+		"missing dsn":    {},
+		"bad driver":     {"-db-dsn", "x", "-db-driver", "oracle"},
+		"bad server id":  {"-db-dsn", "x", "-server-id", "70000"},
+		"bad workers":    {"-db-dsn", "x", "-flusher-workers", "0"},
+		"bad flush":      {"-db-dsn", "x", "-flush-timeout", "0"},
+		"bad cors json":  {"-db-dsn", "x", "-cors-origins", "[]"},
+		"bad cors":       {"-db-dsn", "x", "-cors-origins", `{"news": ["news.example.com"]}`},
+		"bad log level":  {"-db-dsn", "x", "-log-level", "verbose"},
+		"bad log format": {"-db-dsn", "x", "-log-format", "xml"},
+		"bad tracer":     {"-db-dsn", "x", "-tracing", "zipkin"},
+		"bad exporter":   {"-db-dsn", "x", "-otel-traces-exporter", "jaeger"},
+		"missing rules":  {"-db-dsn", "x", "-auth-rules-file", "/nonexistent/rules.json"},
+		"missing jwks":   {"-db-dsn", "x", "-jwt-jwks-files", "/nonexistent/jwks.json"},
+		"missing config": {"-db-dsn", "x", "-services-config", "/nonexistent/services.json"},
+		"bad endpoint":   {"-config-file", writeConfigFile(t, "endpoint.yml", "database:\n  dsn: x\ndiscovery:\n  addr:\n    stats: [\"stats:3000\"]\n")},
+		"unknown key":    {"-config-file", writeConfigFile(t, "unknown.yml", "database:\n  dns: x\n")},
+		"unknown format": {"-config-file", writeConfigFile(t, "config.ini", "")},
+	}
+	for name, args := range cases {
+		if _, err := config.Load("test", args); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/namsral/flag"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/titpetric/microservice/internal"
+)
+
+// Load produces the *Config for name from defaults, config file, environment and args
+//
+// Each flag can be set with an environment variable, upper cased with
+// dashes replaced by underscores, e.g. `-db-dsn` and `DB_DSN`. The config
+// file is set with `-config-file` or CONFIG_FILE.
+func Load(name string, args []string) (*Config, error) {
+	cfg := Default()
+	flags := cfg.flagSet(name)
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if cfg.File != "" {
+		fileConfig := Default()
+		if err := fileConfig.load(cfg.File); err != nil {
+			return nil, err
+		}
+
+		// environment and flags take precedence over the config file
+		fileFlags := fileConfig.flagSet(name)
+		var err error
+		flags.Visit(func(f *flag.Flag) {
+			if err == nil {
+				err = fileFlags.Set(f.Name, f.Value.String())
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		cfg = fileConfig
+	}
+
+	cfg.loadDiscovery(os.Environ())
+
+	if cfg.Migrate.Database.DSN == "" {
+		cfg.Migrate.Database = cfg.Database
+	}
+	if cfg.Migrate.Database.Driver == "" {
+		cfg.Migrate.Database.Driver = cfg.Database.Driver
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(&c.File, "config-file", c.File, "Config file (YAML or TOML)")
+	flags.BoolVar(&c.PrintConfig, "print-config", c.PrintConfig, "Print the effective config and exit")
+
+	flags.StringVar(&c.Server.Addr, "http-addr", c.Server.Addr, "HTTP listen address")
+	flags.UintVar(&c.Server.ID, "server-id", c.Server.ID, "Server ID for sonyflake, 0 uses the private IP")
+	flags.DurationVar(&c.Server.ShutdownTimeout, "shutdown-timeout", c.Server.ShutdownTimeout, "Timeout for draining HTTP requests on shutdown")
+	flags.DurationVar(&c.Server.FlushTimeout, "flush-timeout", c.Server.FlushTimeout, "Timeout for flushing pending data on shutdown")
+
+	flags.StringVar(&c.Database.Driver, "db-driver", c.Database.Driver, "Database driver")
+	flags.StringVar(&c.Database.DSN, "db-dsn", c.Database.DSN, "DSN for database connection")
+
+	flags.BoolVar(&c.Migrate.Enabled, "migrate", c.Migrate.Enabled, "Run migrations?")
+	flags.StringVar(&c.Migrate.Database.Driver, "migrate-db-driver", c.Migrate.Database.Driver, "Migrations: Database driver")
+	flags.StringVar(&c.Migrate.Database.DSN, "migrate-db-dsn", c.Migrate.Database.DSN, "Migrations: DSN for database connection")
+
+	flags.DurationVar(&c.Flusher.Interval, "flusher-interval", c.Flusher.Interval, "Flusher: Time between flushes")
+	flags.IntVar(&c.Flusher.Workers, "flusher-workers", c.Flusher.Workers, "Flusher: Number of queues flushed concurrently")
+	flags.StringVar(&c.Flusher.Sink, "flusher-sink", c.Flusher.Sink, "Flusher: Sinks (db, db-bulk, jsonl, stdout)")
+	flags.StringVar(&c.Flusher.SinkPath, "flusher-sink-path", c.Flusher.SinkPath, "Flusher: Output folder for jsonl sink")
+
+	flags.Var(&jsonValue{&c.CORS.Origins}, "cors-origins", "CORS: Allowed origins per property (JSON)")
+
+	flags.StringVar(&c.Log.Level, "log-level", c.Log.Level, "Log: Minimum level (debug, info, warn, error)")
+	flags.StringVar(&c.Log.Format, "log-format", c.Log.Format, "Log: Format (json, logfmt)")
+
+	flags.StringVar(&c.Tracing.Tracer, "tracing", c.Tracing.Tracer, "Tracing: Backend (elastic, otel, none)")
+	flags.StringVar(&c.Tracing.Exporter, "otel-traces-exporter", c.Tracing.Exporter, "Tracing: OpenTelemetry exporter (otlp, console)")
+
+	flags.StringVar(&c.Auth.RulesFile, "auth-rules-file", c.Auth.RulesFile, "Auth: JSON file with authorization rules")
+	flags.Var(&listValue{&c.Auth.JWKSFiles}, "jwt-jwks-files", "Auth: JWKS files with token keys (comma separated)")
+	flags.StringVar(&c.Auth.Issuer, "jwt-issuer", c.Auth.Issuer, "Auth: Required token issuer")
+	flags.StringVar(&c.Auth.Audience, "jwt-audience", c.Auth.Audience, "Auth: Required token audience")
+
+	flags.BoolVar(&c.Ingest.KeysRequired, "ingest-keys-required", c.Ingest.KeysRequired, "Ingest: Reject pushes for properties without keys")
+
+	flags.StringVar(&c.Discovery.File, "services-config", c.Discovery.File, "Discovery: JSON file with service endpoints")
+	return flags
+}
+
+// loadDiscovery adds `<SERVICE>_SERVICE_ADDR` and `<SERVICE>_SERVICE_SRV` from env
+//
+// Service names are normalized with internal.DiscoveryName.
+func (c *Config) loadDiscovery(environ []string) {
+	addr := map[string][]string{}
+	for service, value := range c.Discovery.Addr {
+		addr[internal.DiscoveryName(service)] = value
+	}
+	srv := map[string]string{}
+	for service, value := range c.Discovery.SRV {
+		srv[internal.DiscoveryName(service)] = value
+	}
+
+	for _, env := range environ {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(parts[0], "_SERVICE_ADDR"):
+			service := internal.DiscoveryName(strings.TrimSuffix(parts[0], "_SERVICE_ADDR"))
+			addr[service] = nil
+			for _, value := range strings.Split(parts[1], ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					addr[service] = append(addr[service], value)
+				}
+			}
+		case strings.HasSuffix(parts[0], "_SERVICE_SRV"):
+			srv[internal.DiscoveryName(strings.TrimSuffix(parts[0], "_SERVICE_SRV"))] = parts[1]
+		}
+	}
+
+	c.Discovery.Addr, c.Discovery.SRV = nil, nil
+	if len(addr) > 0 {
+		c.Discovery.Addr = addr
+	}
+	if len(srv) > 0 {
+		c.Discovery.SRV = srv
+	}
+}
+
+// listValue is a flag.Value for comma separated values
+type listValue struct {
+	value *[]string
+}
+
+func (v *listValue) String() string {
+	if v == nil || v.value == nil {
+		return ""
+	}
+	return strings.Join(*v.value, ",")
+}
+
+func (v *listValue) Set(value string) error {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	*v.value = result
+	return nil
+}
+
+// jsonValue is a flag.Value for JSON encoded values, replacing the current value
+type jsonValue struct {
+	value interface{}
+}
+
+func (v *jsonValue) String() string {
+	if v == nil || v.value == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(v.value)
+	if err != nil || string(encoded) == "null" {
+		return ""
+	}
+	return string(encoded)
+}
+
+func (v *jsonValue) Set(value string) error {
+	target := reflect.ValueOf(v.value).Elem()
+	decoded := reflect.New(target.Type())
+	if err := json.Unmarshal([]byte(value), decoded.Interface()); err != nil {
+		return errors.Wrap(err, "Error decoding JSON")
+	}
+	target.Set(decoded.Elem())
+	return nil
+}
+
+// load decodes a YAML or TOML file over the current values, unknown keys are errors
+func (c *Config) load(filename string) error {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(contents))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(c); err != nil {
+			return errors.Wrapf(err, "Error decoding %s", filename)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(contents), c)
+		if err != nil {
+			return errors.Wrapf(err, "Error decoding %s", filename)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return errors.Errorf("Unknown keys in %s: %v", filename, undecoded)
+		}
+	default:
+		return errors.Errorf("Unsupported config file format: %s", filename)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/titpetric/microservice/db"
+)
+
+// Masked returns a copy of the config with secrets masked
+func (c *Config) Masked() *Config {
+	masked := *c
+	masked.Database.DSN = db.MaskDSN(masked.Database.DSN)
+	masked.Migrate.Database.DSN = db.MaskDSN(masked.Migrate.Database.DSN)
+	return &masked
+}
+
+// Print writes the effective config as YAML, with secrets masked
+func (c *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Masked()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
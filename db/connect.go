@@ -2,24 +2,25 @@ package db
 
 import (
 	"context"
-	"os"
 
 	"database/sql"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 
-	"go.elastic.co/apm/module/apmsql"
+	"github.com/titpetric/microservice/internal"
 
 	// apm specific wrapper for the go mysql driver
 	_ "go.elastic.co/apm/module/apmsql/mysql"
+	// apm specific wrapper for the go postgres driver
+	_ "go.elastic.co/apm/module/apmsql/pq"
 )
 
 // Connect connects to a database and produces the handle for injection
-func Connect(ctx context.Context) (*sqlx.DB, error) {
+func Connect(ctx context.Context, credentials Credentials) (*sqlx.DB, error) {
 	options := ConnectionOptions{
 		Connector: func(ctx context.Context, credentials Credentials) (*sql.DB, error) {
-			db, err := apmsql.Open(credentials.Driver, credentials.DSN)
+			db, err := internal.GetTracer().OpenDB(credentials.Driver, credentials.DSN)
 			if err != nil {
 				return nil, err
 			}
@@ -30,8 +31,7 @@ func Connect(ctx context.Context) (*sqlx.DB, error) {
 			return db, nil
 		},
 	}
-	options.Credentials.DSN = os.Getenv("DB_DSN")
-	options.Credentials.Driver = os.Getenv("DB_DRIVER")
+	options.Credentials = credentials
 	return ConnectWithRetry(ctx, options)
 }
 
@@ -44,7 +44,10 @@ func ConnectWithOptions(ctx context.Context, options ConnectionOptions) (*sqlx.D
 	if credentials.Driver == "" {
 		credentials.Driver = "mysql"
 	}
-	credentials.DSN = cleanDSN(credentials.DSN)
+	if credentials.Driver == "sqlite" {
+		credentials.Driver = "sqlite3"
+	}
+	credentials.DSN = cleanDSN(credentials.Driver, credentials.DSN)
 
 	connect := func() (*sqlx.DB, error) {
 		if options.Connector != nil {
@@ -61,7 +64,15 @@ func ConnectWithOptions(ctx context.Context, options ConnectionOptions) (*sqlx.D
 	if err != nil {
 		return nil, err
 	}
-	db.SetMaxOpenConns(800)
-	db.SetMaxIdleConns(800)
+	switch DialectFor(credentials.Driver) {
+	case DialectSQLite:
+		// sqlite allows a single writer, and each connection
+		// to :memory: would otherwise get a separate database
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+	default:
+		db.SetMaxOpenConns(800)
+		db.SetMaxIdleConns(800)
+	}
 	return db, nil
 }
@@ -0,0 +1,8 @@
+// +build cgo
+
+package db
+
+import (
+	// apm specific wrapper for the go sqlite3 driver (requires cgo)
+	_ "go.elastic.co/apm/module/apmsql/sqlite3"
+)
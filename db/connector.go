@@ -2,7 +2,7 @@ package db
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -11,7 +11,7 @@ import (
 
 // ConnectWithRetry uses retry options set in ConnectionOptions{}
 func ConnectWithRetry(ctx context.Context, options ConnectionOptions) (db *sqlx.DB, err error) {
-	dsn := maskDSN(options.Credentials.DSN)
+	dsn := MaskDSN(options.Credentials.DSN)
 
 	// by default, retry for 5 minutes, 5 seconds between retries
 	if options.Retries == 0 && options.ConnectTimeout.Seconds() == 0 {
@@ -22,7 +22,7 @@ func ConnectWithRetry(ctx context.Context, options ConnectionOptions) (db *sqlx.
 	connErrCh := make(chan error, 1)
 	defer close(connErrCh)
 
-	log.Println("connecting to database", dsn)
+	slog.InfoContext(ctx, "Connecting to database", "dsn", dsn)
 
 	go func() {
 		try := 0
@@ -35,7 +35,7 @@ func ConnectWithRetry(ctx context.Context, options ConnectionOptions) (db *sqlx.
 
 			db, err = ConnectWithOptions(ctx, options)
 			if err != nil {
-				log.Printf("can't connect, dsn=%s, err=%s, try=%d", dsn, err, try)
+				slog.WarnContext(ctx, "Can't connect to database", "dsn", dsn, "err", err, "try", try)
 
 				select {
 				case <-ctx.Done():
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"strings"
+)
+
+// Dialect names a family of SQL syntax supported by the migrations runner
+type Dialect string
+
+const (
+	// DialectMySQL covers MySQL, Percona and MariaDB
+	DialectMySQL Dialect = "mysql"
+	// DialectPostgres covers PostgreSQL
+	DialectPostgres Dialect = "postgres"
+	// DialectSQLite covers SQLite
+	DialectSQLite Dialect = "sqlite"
+)
+
+// dialects lists all known dialects, used as migration filename suffixes
+var dialects = []Dialect{DialectMySQL, DialectPostgres, DialectSQLite}
+
+// DialectFor returns the Dialect for a database driver name
+func DialectFor(driver string) Dialect {
+	switch driver {
+	case "postgres", "pgx":
+		return DialectPostgres
+	case "sqlite", "sqlite3":
+		return DialectSQLite
+	}
+	return DialectMySQL
+}
+
+// Quote quotes an identifier, allowing reserved words like `group` as names
+func (d Dialect) Quote(name string) string {
+	switch d {
+	case DialectPostgres, DialectSQLite:
+		return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+	}
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// upsertQuery produces an insert-or-update query with named parameters
+func (d Dialect) upsertQuery(table string, fields, primary []string) string {
+	named := ":" + strings.Join(fields, ",:")
+	switch d {
+	case DialectPostgres:
+		set := []string{}
+		for _, field := range fields {
+			set = append(set, field+"=excluded."+field)
+		}
+		return "insert into " + table + " (" + strings.Join(fields, ",") + ") values (" + named + ")" +
+			" on conflict (" + strings.Join(primary, ",") + ") do update set " + strings.Join(set, ", ")
+	}
+	return "replace into " + table + " (" + strings.Join(fields, ",") + ") values (" + named + ")"
+}
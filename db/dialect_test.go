@@ -0,0 +1,24 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestDialectQuote(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		name     string
+		expected string
+	}{
+		{DialectMySQL, "group", "`group`"},
+		{DialectMySQL, "a`b", "`a``b`"},
+		{DialectPostgres, "group", `"group"`},
+		{DialectPostgres, `a"b`, `"a""b"`},
+		{DialectSQLite, "group", `"group"`},
+	}
+	for _, c := range cases {
+		if result := c.dialect.Quote(c.name); result != c.expected {
+			t.Errorf("Unexpected quoted name for %s: %s != %s", c.dialect, result, c.expected)
+		}
+	}
+}
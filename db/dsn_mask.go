@@ -2,8 +2,13 @@ package db
 
 import "regexp"
 
-var dsnMasker = regexp.MustCompile("(.)(?:.*)(.):(.)(?:.*)(.)@")
+var (
+	dsnMasker         = regexp.MustCompile("(.)(?:.*)(.):(.)(?:.*)(.)@")
+	dsnPasswordMasker = regexp.MustCompile("password=(?:'[^']*'|[^ ]*)")
+)
 
-func maskDSN(dsn string) string {
+// MaskDSN hides credentials in a DSN for logging
+func MaskDSN(dsn string) string {
+	dsn = dsnPasswordMasker.ReplaceAllString(dsn, "password=****")
 	return dsnMasker.ReplaceAllString(dsn, "$1****$2:$3****$4@")
 }
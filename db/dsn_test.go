@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestCleanDSN(t *testing.T) {
+	cases := []struct {
+		driver, dsn, expected string
+	}{
+		{"mysql", "stats:stats@tcp(db:3306)/stats", "stats:stats@tcp(db:3306)/stats?collation=utf8_general_ci&parseTime=true&loc=Local"},
+		{"mysql", "stats:stats@tcp(db:3306)/stats?parseTime=false", "stats:stats@tcp(db:3306)/stats?parseTime=false&collation=utf8_general_ci&loc=Local"},
+		{"postgres", "postgres://stats:stats@db/stats", "postgres://stats:stats@db/stats?sslmode=disable"},
+		{"postgres", "postgres://stats:stats@db/stats?sslmode=require", "postgres://stats:stats@db/stats?sslmode=require"},
+		{"postgres", "user=stats password=stats host=db dbname=stats", "user=stats password=stats host=db dbname=stats sslmode=disable"},
+		{"sqlite3", ":memory:", ":memory:?_busy_timeout=5000"},
+		{"sqlite3", "file:stats.db?mode=rwc", "file:stats.db?mode=rwc&_busy_timeout=5000"},
+	}
+	for _, c := range cases {
+		if result := cleanDSN(c.driver, c.dsn); result != c.expected {
+			t.Errorf("Unexpected DSN for %s: %s != %s", c.driver, result, c.expected)
+		}
+	}
+}
+
+func TestMaskDSN(t *testing.T) {
+	cases := []struct {
+		dsn, expected string
+	}{
+		{"stats:stats@tcp(db:3306)/stats", "s****s:s****s@tcp(db:3306)/stats"},
+		{"user=stats password=secret host=db", "user=stats password=**** host=db"},
+	}
+	for _, c := range cases {
+		if result := MaskDSN(c.dsn); result != c.expected {
+			t.Errorf("Unexpected masked DSN: %s != %s", result, c.expected)
+		}
+	}
+}
@@ -3,28 +3,77 @@ package db
 import (
 	"os"
 	"sort"
+	"strings"
 
 	"encoding/base64"
 	"path/filepath"
+
+	"github.com/pkg/errors"
 )
 
 // FS represents a mapping between filename => contents
 type FS map[string]string
 
-// Migrations returns list of SQL files to execute
-func (fs FS) Migrations() []string {
-	result := []string{}
+// Migrations returns list of SQL files to execute for a dialect
+//
+// Migrations may be provided as a generic `*.up.sql` file, and/or as
+// dialect specific `*.[dialect].up.sql` files. A dialect specific file
+// is preferred over the generic one. If a migration only has dialect
+// specific files, but none for the given dialect, an error is returned.
+func (fs FS) Migrations(dialect Dialect) ([]string, error) {
+	variants := map[string]map[Dialect]string{}
 	for filename, contents := range fs {
 		// skip empty files
 		if contents == "" {
 			continue
 		}
 		if matched, _ := filepath.Match("*.up.sql", filename); matched {
+			name, fileDialect := migrationName(filename)
+			if _, ok := variants[name]; !ok {
+				variants[name] = map[Dialect]string{}
+			}
+			variants[name][fileDialect] = filename
+		}
+	}
+
+	result := []string{}
+	for name, files := range variants {
+		if filename, ok := files[dialect]; ok {
 			result = append(result, filename)
+			continue
 		}
+		if filename, ok := files[""]; ok {
+			result = append(result, filename)
+			continue
+		}
+		return nil, errors.Errorf("Migration %s doesn't exist for dialect '%s'", name, dialect)
 	}
 	sort.Strings(result)
-	return result
+	return result, nil
+}
+
+// DownMigration returns the SQL file reverting a migration for a dialect
+//
+// The migration is given by its generic filename, as logged in the
+// migrations table. A dialect specific `*.[dialect].down.sql` file is
+// preferred over the generic `*.down.sql` file.
+func (fs FS) DownMigration(filename string, dialect Dialect) (string, error) {
+	name := strings.TrimSuffix(filename, ".up.sql")
+	for _, candidate := range []string{name + "." + string(dialect) + ".down.sql", name + ".down.sql"} {
+		if fs[candidate] != "" {
+			return candidate, nil
+		}
+	}
+	return "", errors.Errorf("Down migration for %s doesn't exist for dialect '%s'", filename, dialect)
+}
+
+// MigrationsTable returns the SQL file creating the migrations table
+func (fs FS) MigrationsTable(dialect Dialect) string {
+	filename := "migrations." + string(dialect) + ".sql"
+	if _, ok := fs[filename]; ok {
+		return filename
+	}
+	return "migrations.sql"
 }
 
 // ReadFile returns decoded file contents from FS
@@ -34,3 +83,18 @@ func (fs FS) ReadFile(filename string) ([]byte, error) {
 	}
 	return nil, os.ErrNotExist
 }
+
+// migrationName returns the generic migration filename and dialect
+//
+// For `2019-12-13-184604-init.mysql.up.sql`, the result is the filename
+// `2019-12-13-184604-init.up.sql` and DialectMySQL. Generic migration
+// filenames are returned as-is, with an empty dialect.
+func migrationName(filename string) (string, Dialect) {
+	name := strings.TrimSuffix(filename, ".up.sql")
+	for _, dialect := range dialects {
+		if strings.HasSuffix(name, "."+string(dialect)) {
+			return strings.TrimSuffix(name, "."+string(dialect)) + ".up.sql", dialect
+		}
+	}
+	return filename, ""
+}
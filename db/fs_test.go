@@ -0,0 +1,71 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFSMigrations(t *testing.T) {
+	fs := FS{
+		"migrations.sql":                      "-",
+		"migrations.postgres.sql":             "-",
+		"2019-01-01-000000-a.up.sql":          "-",
+		"2019-01-02-000000-b.up.sql":          "-",
+		"2019-01-02-000000-b.sqlite.up.sql":   "-",
+		"2019-01-03-000000-c.mysql.up.sql":    "-",
+		"2019-01-03-000000-c.postgres.up.sql": "-",
+		"2019-01-04-000000-d.up.sql":          "",
+	}
+
+	cases := map[Dialect]string{
+		DialectMySQL:    "2019-01-01-000000-a.up.sql 2019-01-02-000000-b.up.sql 2019-01-03-000000-c.mysql.up.sql",
+		DialectPostgres: "2019-01-01-000000-a.up.sql 2019-01-02-000000-b.up.sql 2019-01-03-000000-c.postgres.up.sql",
+	}
+	for dialect, expected := range cases {
+		filenames, err := fs.Migrations(dialect)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %+v", dialect, err)
+		}
+		if result := strings.Join(filenames, " "); result != expected {
+			t.Errorf("Unexpected migrations for %s: %s != %s", dialect, result, expected)
+		}
+	}
+
+	if _, err := fs.Migrations(DialectSQLite); err == nil {
+		t.Errorf("Expected error for missing sqlite migration")
+	}
+
+	if table := fs.MigrationsTable(DialectPostgres); table != "migrations.postgres.sql" {
+		t.Errorf("Unexpected migrations table for postgres: %s", table)
+	}
+	if table := fs.MigrationsTable(DialectMySQL); table != "migrations.sql" {
+		t.Errorf("Unexpected migrations table for mysql: %s", table)
+	}
+}
+
+func TestFSDownMigration(t *testing.T) {
+	fs := FS{
+		"2019-01-01-000000-a.up.sql":            "-",
+		"2019-01-01-000000-a.down.sql":          "-",
+		"2019-01-01-000000-a.postgres.down.sql": "-",
+		"2019-01-02-000000-b.up.sql":            "-",
+	}
+
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{DialectMySQL, "2019-01-01-000000-a.down.sql"},
+		{DialectPostgres, "2019-01-01-000000-a.postgres.down.sql"},
+	}
+	for _, c := range cases {
+		filename, err := fs.DownMigration("2019-01-01-000000-a.up.sql", c.dialect)
+		if err != nil || filename != c.expected {
+			t.Errorf("Unexpected down migration for %s: %s != %s, err %+v", c.dialect, filename, c.expected, err)
+		}
+	}
+
+	if _, err := fs.DownMigration("2019-01-02-000000-b.up.sql", DialectMySQL); err == nil {
+		t.Errorf("Expected error for missing down migration")
+	}
+}
@@ -7,13 +7,18 @@ import (
 	"github.com/pkg/errors"
 )
 
-// Print outputs database migrations for a project to log output
-func Print(project string) error {
+// Print outputs database migrations for a project and dialect to log output
+func Print(project string, dialect Dialect) error {
 	fs, ok := migrations[project]
 	if !ok {
 		return errors.Errorf("Migrations for '%s' don't exist", project)
 	}
 
+	filenames, err := fs.Migrations(dialect)
+	if err != nil {
+		return err
+	}
+
 	printQuery := func(idx int, query string) error {
 		log.Println()
 		log.Println("-- Statement index:", idx)
@@ -37,12 +42,12 @@ func Print(project string) error {
 	}
 
 	// print main migration
-	if err := migrate("migrations.sql"); err != nil {
+	if err := migrate(fs.MigrationsTable(dialect)); err != nil {
 		return err
 	}
 
 	// print service migrations
-	for _, filename := range fs.Migrations() {
+	for _, filename := range filenames {
 		if err := migrate(filename); err != nil {
 			return err
 		}
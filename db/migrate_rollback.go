@@ -0,0 +1,95 @@
+package db
+
+import (
+	"fmt"
+	"log/slog"
+
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Rollback reverts the last `steps` applied migrations for a project
+//
+// Down migrations run in reverse order, and each reverted migration is
+// removed from the migrations table, so Run applies it again.
+func Rollback(project string, steps int, db *sqlx.DB) error {
+	if steps < 1 {
+		return errors.Errorf("Rollback steps must be at least 1, got %d", steps)
+	}
+	applied, err := appliedMigrations(project, db)
+	if err != nil {
+		return err
+	}
+	if steps > len(applied) {
+		return errors.Errorf("Can't roll back %d migrations for '%s', %d applied", steps, project, len(applied))
+	}
+	return rollback(project, applied[:steps], db)
+}
+
+// RollbackTo reverts all migrations applied after filename for a project
+//
+// The migration in filename stays applied. Both generic and dialect
+// specific filenames are accepted.
+func RollbackTo(project string, filename string, db *sqlx.DB) error {
+	name, _ := migrationName(filename)
+	applied, err := appliedMigrations(project, db)
+	if err != nil {
+		return err
+	}
+	for idx, appliedName := range applied {
+		if appliedName == name {
+			return rollback(project, applied[:idx], db)
+		}
+	}
+	return errors.Errorf("Migration %s isn't applied for '%s'", name, project)
+}
+
+// appliedMigrations lists successfully applied migrations, newest first
+func appliedMigrations(project string, db *sqlx.DB) ([]string, error) {
+	if _, ok := migrations[project]; !ok {
+		return nil, errors.Errorf("Migrations for '%s' don't exist", project)
+	}
+	result := []string{}
+	query := db.Rebind("select filename from migrations where project=? and status='ok' order by filename desc")
+	if err := db.Select(&result, query, project); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return result, nil
+}
+
+func rollback(project string, names []string, db *sqlx.DB) error {
+	fs := migrations[project]
+	dialect := DialectFor(db.DriverName())
+
+	// check all down migrations exist before changing anything
+	filenames := make([]string, len(names))
+	for idx, name := range names {
+		filename, err := fs.DownMigration(name, dialect)
+		if err != nil {
+			return err
+		}
+		filenames[idx] = filename
+	}
+
+	for idx, filename := range filenames {
+		slog.Info("Rolling back migrations", "filename", filename)
+
+		stmts, err := statements(fs.ReadFile(filename))
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Error reading migration: %s", filename))
+		}
+		for stmtIdx, stmt := range stmts {
+			slog.Debug("Running rollback statement", "index", stmtIdx, "query", stmt)
+			if _, err := db.Exec(stmt); err != nil && err != sql.ErrNoRows {
+				return errors.Wrap(err, fmt.Sprintf("Error rolling back migration: %s, statement %d", filename, stmtIdx))
+			}
+		}
+
+		if _, err := db.Exec(db.Rebind("delete from migrations where project=? and filename=?"), project, names[idx]); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Error removing migration status: %s", names[idx]))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// +build cgo
+
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRollback(t *testing.T) {
+	assert := func(ok bool, format string, params ...interface{}) {
+		if !ok {
+			t.Fatalf(format, params...)
+		}
+	}
+
+	options := ConnectionOptions{}
+	options.Credentials.Driver = "sqlite"
+	options.Credentials.DSN = ":memory:"
+
+	handle, err := ConnectWithOptions(context.Background(), options)
+	assert(err == nil, "Unexpected error when connecting: %+v", err)
+	defer handle.Close()
+
+	tableExists := func(table string) bool {
+		var count int
+		err := handle.Get(&count, "select count(*) from sqlite_master where type='table' and name=?", table)
+		assert(err == nil, "Unexpected error checking table %s: %+v", table, err)
+		return count == 1
+	}
+
+	applied := func() []string {
+		result, err := appliedMigrations("stats", handle)
+		assert(err == nil, "Unexpected error listing migrations: %+v", err)
+		return result
+	}
+
+	assert(Run("stats", handle) == nil, "Unexpected error running migrations")
+	assert(len(applied()) == 2, "Expected 2 applied migrations, got %v", applied())
+
+	err = Rollback("stats", 1, handle)
+	assert(err == nil, "Unexpected error on Rollback: %+v", err)
+	assert(!tableExists("ingest_keys") && tableExists("incoming"), "Expected ingest_keys to be dropped")
+	assert(len(applied()) == 1, "Expected 1 applied migration, got %v", applied())
+
+	// migrations apply again after a rollback
+	assert(Run("stats", handle) == nil, "Unexpected error running migrations")
+	assert(tableExists("ingest_keys"), "Expected ingest_keys to be created again")
+
+	err = RollbackTo("stats", "2019-12-13-184604-import-initial-schema.sqlite.up.sql", handle)
+	assert(err == nil, "Unexpected error on RollbackTo: %+v", err)
+	assert(!tableExists("ingest_keys") && tableExists("incoming"), "Expected rollback to the initial schema")
+
+	err = RollbackTo("stats", "2026-10-18-150000-ingest-keys.up.sql", handle)
+	assert(err != nil, "Expected error on RollbackTo a migration which isn't applied")
+
+	err = Rollback("stats", 2, handle)
+	assert(err != nil, "Expected error on Rollback past applied migrations")
+
+	err = Rollback("stats", 1, handle)
+	assert(err == nil, "Unexpected error on Rollback: %+v", err)
+	assert(!tableExists("incoming") && !tableExists("incoming_proc"), "Expected initial schema to be dropped")
+	assert(len(applied()) == 0, "Expected no applied migrations, got %v", applied())
+}
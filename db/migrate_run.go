@@ -2,8 +2,7 @@ package db
 
 import (
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 
 	"database/sql"
 
@@ -18,12 +17,16 @@ func Run(project string, db *sqlx.DB) error {
 		return errors.Errorf("Migrations for '%s' don't exist", project)
 	}
 
+	dialect := DialectFor(db.DriverName())
+	migrationsTable := fs.MigrationsTable(dialect)
+	filenames, err := fs.Migrations(dialect)
+	if err != nil {
+		return err
+	}
+
 	execQuery := func(idx int, query string, useLog bool) error {
 		if useLog {
-			log.Println()
-			log.Println("-- Statement index:", idx)
-			log.Println(query)
-			log.Println()
+			slog.Debug("Running migration statement", "index", idx, "query", query)
 		}
 		if _, err := db.Exec(query); err != nil && err != sql.ErrNoRows {
 			return err
@@ -32,21 +35,23 @@ func Run(project string, db *sqlx.DB) error {
 	}
 
 	migrate := func(filename string) error {
-		log.Println("Running migrations from", filename)
+		slog.Info("Running migrations", "filename", filename)
 
+		// dialect specific files log status under the generic filename
+		name, _ := migrationName(filename)
 		status := migration{
 			Project:  project,
-			Filename: filename,
+			Filename: name,
 		}
 
 		// we can't log the main migrations table
-		useLog := (filename != "migrations.sql")
+		useLog := (filename != migrationsTable)
 		if useLog {
-			if err := db.Get(&status, "select * from migrations where project=? and filename=?", status.Project, status.Filename); err != nil && err != sql.ErrNoRows {
+			if err := db.Get(&status, db.Rebind("select * from migrations where project=? and filename=?"), status.Project, status.Filename); err != nil && err != sql.ErrNoRows {
 				return err
 			}
 			if status.Status == "ok" {
-				log.Println("Migrations already applied, skipping")
+				slog.Info("Migrations already applied, skipping", "filename", filename)
 				return nil
 			}
 		}
@@ -74,27 +79,21 @@ func Run(project string, db *sqlx.DB) error {
 		err := up()
 		if useLog {
 			// log the migration status into the database
-			set := func(fields []string) string {
-				sql := make([]string, len(fields))
-				for k, v := range fields {
-					sql[k] = v + "=:" + v
-				}
-				return strings.Join(sql, ", ")
-			}
-			if _, err := db.NamedExec("replace into migrations set "+set(migrationFields), status); err != nil {
-				log.Println("Updating migration status failed:", err)
+			query := dialect.upsertQuery("migrations", migrationFields, migrationPrimaryFields)
+			if _, err := db.NamedExec(query, status); err != nil {
+				slog.Error("Updating migration status failed", "filename", filename, "err", err)
 			}
 		}
 		return err
 	}
 
 	// run main migration
-	if err := migrate("migrations.sql"); err != nil {
+	if err := migrate(migrationsTable); err != nil {
 		return err
 	}
 
 	// run service migrations
-	for _, filename := range fs.Migrations() {
+	for _, filename := range filenames {
 		if err := migrate(filename); err != nil {
 			return err
 		}
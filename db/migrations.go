@@ -14,7 +14,10 @@ type (
 	}
 )
 
-var migrationFields = []string{"project", "filename", "statement_index", "status"}
+var (
+	migrationFields        = []string{"project", "filename", "statement_index", "status"}
+	migrationPrimaryFields = []string{"project", "filename"}
+)
 
 func statements(contents []byte, err error) ([]string, error) {
 	result := []string{}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS migrations (
+ project varchar(16) NOT NULL,
+ filename varchar(255) NOT NULL,
+ statement_index integer NOT NULL,
+ status text NOT NULL,
+ PRIMARY KEY (project, filename)
+);
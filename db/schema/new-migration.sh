@@ -9,5 +9,5 @@ if [ ! -d "$service" ]; then
 	mkdir $service
 fi
 
-cp migrations.sql $service/
+cp migrations*.sql $service/
 touch $service/$(date +%Y-%m-%d-%H%M%S)-description-here.up.sql
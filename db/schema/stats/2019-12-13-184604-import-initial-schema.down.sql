@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS incoming_proc;
+DROP TABLE IF EXISTS incoming;
@@ -0,0 +1,19 @@
+CREATE TABLE incoming (
+ id bigint NOT NULL,
+ property varchar(32) NOT NULL,
+ property_section integer NOT NULL,
+ property_id integer NOT NULL,
+ remote_ip varchar(255) NOT NULL,
+ stamp timestamp NOT NULL,
+ PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE incoming IS 'Incoming stats log, writes only';
+COMMENT ON COLUMN incoming.id IS 'Tracking ID';
+COMMENT ON COLUMN incoming.property IS 'Property name (human readable, a-z)';
+COMMENT ON COLUMN incoming.property_section IS 'Property Section ID';
+COMMENT ON COLUMN incoming.property_id IS 'Property Item ID';
+COMMENT ON COLUMN incoming.remote_ip IS 'Remote IP from user making request';
+COMMENT ON COLUMN incoming.stamp IS 'Timestamp of request';
+
+CREATE TABLE incoming_proc (LIKE incoming INCLUDING ALL);
@@ -0,0 +1,19 @@
+CREATE TABLE incoming (
+ id integer NOT NULL,
+ property varchar(32) NOT NULL,
+ property_section integer NOT NULL,
+ property_id integer NOT NULL,
+ remote_ip varchar(255) NOT NULL,
+ stamp datetime NOT NULL,
+ PRIMARY KEY (id)
+);
+
+CREATE TABLE incoming_proc (
+ id integer NOT NULL,
+ property varchar(32) NOT NULL,
+ property_section integer NOT NULL,
+ property_id integer NOT NULL,
+ remote_ip varchar(255) NOT NULL,
+ stamp datetime NOT NULL,
+ PRIMARY KEY (id)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE `ingest_keys` (
+ `key_id` varchar(64) COLLATE utf8_slovenian_ci NOT NULL COMMENT 'Key ID, sent with signed requests',
+ `property` varchar(32) COLLATE utf8_slovenian_ci NOT NULL COMMENT 'Property name the key can push to',
+ `secret` varchar(128) COLLATE utf8_slovenian_ci NOT NULL DEFAULT '' COMMENT 'HMAC secret, empty if signing is disabled',
+ `api_key_hash` varchar(64) COLLATE utf8_slovenian_ci NOT NULL DEFAULT '' COMMENT 'Hex SHA-256 of the API key, empty if disabled',
+ `created_at` datetime NOT NULL COMMENT 'Key creation time',
+ `expires_at` datetime NULL COMMENT 'Key expiry time, NULL for no expiry',
+ PRIMARY KEY (`key_id`),
+ KEY `property` (`property`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_slovenian_ci COMMENT='Ingestion credentials per property';
@@ -0,0 +1,19 @@
+CREATE TABLE ingest_keys (
+ key_id varchar(64) NOT NULL,
+ property varchar(32) NOT NULL,
+ secret varchar(128) NOT NULL DEFAULT '',
+ api_key_hash varchar(64) NOT NULL DEFAULT '',
+ created_at timestamp NOT NULL,
+ expires_at timestamp NULL,
+ PRIMARY KEY (key_id)
+);
+
+CREATE INDEX ingest_keys_property ON ingest_keys (property);
+
+COMMENT ON TABLE ingest_keys IS 'Ingestion credentials per property';
+COMMENT ON COLUMN ingest_keys.key_id IS 'Key ID, sent with signed requests';
+COMMENT ON COLUMN ingest_keys.property IS 'Property name the key can push to';
+COMMENT ON COLUMN ingest_keys.secret IS 'HMAC secret, empty if signing is disabled';
+COMMENT ON COLUMN ingest_keys.api_key_hash IS 'Hex SHA-256 of the API key, empty if disabled';
+COMMENT ON COLUMN ingest_keys.created_at IS 'Key creation time';
+COMMENT ON COLUMN ingest_keys.expires_at IS 'Key expiry time, NULL for no expiry';
@@ -0,0 +1,11 @@
+CREATE TABLE ingest_keys (
+ key_id varchar(64) NOT NULL,
+ property varchar(32) NOT NULL,
+ secret varchar(128) NOT NULL DEFAULT '',
+ api_key_hash varchar(64) NOT NULL DEFAULT '',
+ created_at datetime NOT NULL,
+ expires_at datetime NULL,
+ PRIMARY KEY (key_id)
+);
+
+CREATE INDEX ingest_keys_property ON ingest_keys (property);
@@ -1,6 +1,15 @@
 package db
 
 var stats FS = FS{
-	"2019-12-13-184604-import-initial-schema.up.sql": "Q1JFQVRFIFRBQkxFIGBpbmNvbWluZ2AgKAogYGlkYCBiaWdpbnQoMjApIHVuc2lnbmVkIE5PVCBOVUxMIENPTU1FTlQgJ1RyYWNraW5nIElEJywKIGBwcm9wZXJ0eWAgdmFyY2hhcigzMikgQ09MTEFURSB1dGY4X3Nsb3Zlbmlhbl9jaSBOT1QgTlVMTCBDT01NRU5UICdQcm9wZXJ0eSBuYW1lIChodW1hbiByZWFkYWJsZSwgYS16KScsCiBgcHJvcGVydHlfc2VjdGlvbmAgaW50KDExKSB1bnNpZ25lZCBOT1QgTlVMTCBDT01NRU5UICdQcm9wZXJ0eSBTZWN0aW9uIElEJywKIGBwcm9wZXJ0eV9pZGAgaW50KDExKSB1bnNpZ25lZCBOT1QgTlVMTCBDT01NRU5UICdQcm9wZXJ0eSBJdGVtIElEJywKIGByZW1vdGVfaXBgIHZhcmNoYXIoMjU1KSBDT0xMQVRFIHV0Zjhfc2xvdmVuaWFuX2NpIE5PVCBOVUxMIENPTU1FTlQgJ1JlbW90ZSBJUCBmcm9tIHVzZXIgbWFraW5nIHJlcXVlc3QnLAogYHN0YW1wYCBkYXRldGltZSBOT1QgTlVMTCBDT01NRU5UICdUaW1lc3RhbXAgb2YgcmVxdWVzdCcsCiBQUklNQVJZIEtFWSAoYGlkYCkKKSBFTkdJTkU9SW5ub0RCIERFRkFVTFQgQ0hBUlNFVD11dGY4IENPTExBVEU9dXRmOF9zbG92ZW5pYW5fY2kgQ09NTUVOVD0nSW5jb21pbmcgc3RhdHMgbG9nLCB3cml0ZXMgb25seSc7CgpDUkVBVEUgVEFCTEUgYGluY29taW5nX3Byb2NgIExJS0UgYGluY29taW5nYDsK",
-	"migrations.sql": "Q1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgYG1pZ3JhdGlvbnNgICgKIGBwcm9qZWN0YCB2YXJjaGFyKDE2KSBOT1QgTlVMTCBDT01NRU5UICdNaWNyb3NlcnZpY2Ugb3IgcHJvamVjdCBuYW1lJywKIGBmaWxlbmFtZWAgdmFyY2hhcigyNTUpIE5PVCBOVUxMIENPTU1FTlQgJ3l5eXktbW0tZGQtSEhNTVNTLnNxbCcsCiBgc3RhdGVtZW50X2luZGV4YCBpbnQoMTEpIE5PVCBOVUxMIENPTU1FTlQgJ1N0YXRlbWVudCBudW1iZXIgZnJvbSBTUUwgZmlsZScsCiBgc3RhdHVzYCB0ZXh0IE5PVCBOVUxMIENPTU1FTlQgJ29rIG9yIGZ1bGwgZXJyb3IgbWVzc2FnZScsCiBQUklNQVJZIEtFWSAoYHByb2plY3RgLGBmaWxlbmFtZWApCikgRU5HSU5FPUlubm9EQiBERUZBVUxUIENIQVJTRVQ9dXRmODsK",
+	"2019-12-13-184604-import-initial-schema.down.sql":        "RFJPUCBUQUJMRSBJRiBFWElTVFMgaW5jb21pbmdfcHJvYzsKRFJPUCBUQUJMRSBJRiBFWElTVFMgaW5jb21pbmc7Cg==",
+	"2019-12-13-184604-import-initial-schema.mysql.up.sql":    "Q1JFQVRFIFRBQkxFIGBpbmNvbWluZ2AgKAogYGlkYCBiaWdpbnQoMjApIHVuc2lnbmVkIE5PVCBOVUxMIENPTU1FTlQgJ1RyYWNraW5nIElEJywKIGBwcm9wZXJ0eWAgdmFyY2hhcigzMikgQ09MTEFURSB1dGY4X3Nsb3Zlbmlhbl9jaSBOT1QgTlVMTCBDT01NRU5UICdQcm9wZXJ0eSBuYW1lIChodW1hbiByZWFkYWJsZSwgYS16KScsCiBgcHJvcGVydHlfc2VjdGlvbmAgaW50KDExKSB1bnNpZ25lZCBOT1QgTlVMTCBDT01NRU5UICdQcm9wZXJ0eSBTZWN0aW9uIElEJywKIGBwcm9wZXJ0eV9pZGAgaW50KDExKSB1bnNpZ25lZCBOT1QgTlVMTCBDT01NRU5UICdQcm9wZXJ0eSBJdGVtIElEJywKIGByZW1vdGVfaXBgIHZhcmNoYXIoMjU1KSBDT0xMQVRFIHV0Zjhfc2xvdmVuaWFuX2NpIE5PVCBOVUxMIENPTU1FTlQgJ1JlbW90ZSBJUCBmcm9tIHVzZXIgbWFraW5nIHJlcXVlc3QnLAogYHN0YW1wYCBkYXRldGltZSBOT1QgTlVMTCBDT01NRU5UICdUaW1lc3RhbXAgb2YgcmVxdWVzdCcsCiBQUklNQVJZIEtFWSAoYGlkYCkKKSBFTkdJTkU9SW5ub0RCIERFRkFVTFQgQ0hBUlNFVD11dGY4IENPTExBVEU9dXRmOF9zbG92ZW5pYW5fY2kgQ09NTUVOVD0nSW5jb21pbmcgc3RhdHMgbG9nLCB3cml0ZXMgb25seSc7CgpDUkVBVEUgVEFCTEUgYGluY29taW5nX3Byb2NgIExJS0UgYGluY29taW5nYDsK",
+	"2019-12-13-184604-import-initial-schema.postgres.up.sql": "Q1JFQVRFIFRBQkxFIGluY29taW5nICgKIGlkIGJpZ2ludCBOT1QgTlVMTCwKIHByb3BlcnR5IHZhcmNoYXIoMzIpIE5PVCBOVUxMLAogcHJvcGVydHlfc2VjdGlvbiBpbnRlZ2VyIE5PVCBOVUxMLAogcHJvcGVydHlfaWQgaW50ZWdlciBOT1QgTlVMTCwKIHJlbW90ZV9pcCB2YXJjaGFyKDI1NSkgTk9UIE5VTEwsCiBzdGFtcCB0aW1lc3RhbXAgTk9UIE5VTEwsCiBQUklNQVJZIEtFWSAoaWQpCik7CgpDT01NRU5UIE9OIFRBQkxFIGluY29taW5nIElTICdJbmNvbWluZyBzdGF0cyBsb2csIHdyaXRlcyBvbmx5JzsKQ09NTUVOVCBPTiBDT0xVTU4gaW5jb21pbmcuaWQgSVMgJ1RyYWNraW5nIElEJzsKQ09NTUVOVCBPTiBDT0xVTU4gaW5jb21pbmcucHJvcGVydHkgSVMgJ1Byb3BlcnR5IG5hbWUgKGh1bWFuIHJlYWRhYmxlLCBhLXopJzsKQ09NTUVOVCBPTiBDT0xVTU4gaW5jb21pbmcucHJvcGVydHlfc2VjdGlvbiBJUyAnUHJvcGVydHkgU2VjdGlvbiBJRCc7CkNPTU1FTlQgT04gQ09MVU1OIGluY29taW5nLnByb3BlcnR5X2lkIElTICdQcm9wZXJ0eSBJdGVtIElEJzsKQ09NTUVOVCBPTiBDT0xVTU4gaW5jb21pbmcucmVtb3RlX2lwIElTICdSZW1vdGUgSVAgZnJvbSB1c2VyIG1ha2luZyByZXF1ZXN0JzsKQ09NTUVOVCBPTiBDT0xVTU4gaW5jb21pbmcuc3RhbXAgSVMgJ1RpbWVzdGFtcCBvZiByZXF1ZXN0JzsKCkNSRUFURSBUQUJMRSBpbmNvbWluZ19wcm9jIChMSUtFIGluY29taW5nIElOQ0xVRElORyBBTEwpOwo=",
+	"2019-12-13-184604-import-initial-schema.sqlite.up.sql":   "Q1JFQVRFIFRBQkxFIGluY29taW5nICgKIGlkIGludGVnZXIgTk9UIE5VTEwsCiBwcm9wZXJ0eSB2YXJjaGFyKDMyKSBOT1QgTlVMTCwKIHByb3BlcnR5X3NlY3Rpb24gaW50ZWdlciBOT1QgTlVMTCwKIHByb3BlcnR5X2lkIGludGVnZXIgTk9UIE5VTEwsCiByZW1vdGVfaXAgdmFyY2hhcigyNTUpIE5PVCBOVUxMLAogc3RhbXAgZGF0ZXRpbWUgTk9UIE5VTEwsCiBQUklNQVJZIEtFWSAoaWQpCik7CgpDUkVBVEUgVEFCTEUgaW5jb21pbmdfcHJvYyAoCiBpZCBpbnRlZ2VyIE5PVCBOVUxMLAogcHJvcGVydHkgdmFyY2hhcigzMikgTk9UIE5VTEwsCiBwcm9wZXJ0eV9zZWN0aW9uIGludGVnZXIgTk9UIE5VTEwsCiBwcm9wZXJ0eV9pZCBpbnRlZ2VyIE5PVCBOVUxMLAogcmVtb3RlX2lwIHZhcmNoYXIoMjU1KSBOT1QgTlVMTCwKIHN0YW1wIGRhdGV0aW1lIE5PVCBOVUxMLAogUFJJTUFSWSBLRVkgKGlkKQopOwo=",
+	"2026-10-18-150000-ingest-keys.down.sql":                  "RFJPUCBUQUJMRSBJRiBFWElTVFMgaW5nZXN0X2tleXM7Cg==",
+	"2026-10-18-150000-ingest-keys.mysql.up.sql":              "Q1JFQVRFIFRBQkxFIGBpbmdlc3Rfa2V5c2AgKAogYGtleV9pZGAgdmFyY2hhcig2NCkgQ09MTEFURSB1dGY4X3Nsb3Zlbmlhbl9jaSBOT1QgTlVMTCBDT01NRU5UICdLZXkgSUQsIHNlbnQgd2l0aCBzaWduZWQgcmVxdWVzdHMnLAogYHByb3BlcnR5YCB2YXJjaGFyKDMyKSBDT0xMQVRFIHV0Zjhfc2xvdmVuaWFuX2NpIE5PVCBOVUxMIENPTU1FTlQgJ1Byb3BlcnR5IG5hbWUgdGhlIGtleSBjYW4gcHVzaCB0bycsCiBgc2VjcmV0YCB2YXJjaGFyKDEyOCkgQ09MTEFURSB1dGY4X3Nsb3Zlbmlhbl9jaSBOT1QgTlVMTCBERUZBVUxUICcnIENPTU1FTlQgJ0hNQUMgc2VjcmV0LCBlbXB0eSBpZiBzaWduaW5nIGlzIGRpc2FibGVkJywKIGBhcGlfa2V5X2hhc2hgIHZhcmNoYXIoNjQpIENPTExBVEUgdXRmOF9zbG92ZW5pYW5fY2kgTk9UIE5VTEwgREVGQVVMVCAnJyBDT01NRU5UICdIZXggU0hBLTI1NiBvZiB0aGUgQVBJIGtleSwgZW1wdHkgaWYgZGlzYWJsZWQnLAogYGNyZWF0ZWRfYXRgIGRhdGV0aW1lIE5PVCBOVUxMIENPTU1FTlQgJ0tleSBjcmVhdGlvbiB0aW1lJywKIGBleHBpcmVzX2F0YCBkYXRldGltZSBOVUxMIENPTU1FTlQgJ0tleSBleHBpcnkgdGltZSwgTlVMTCBmb3Igbm8gZXhwaXJ5JywKIFBSSU1BUlkgS0VZIChga2V5X2lkYCksCiBLRVkgYHByb3BlcnR5YCAoYHByb3BlcnR5YCkKKSBFTkdJTkU9SW5ub0RCIERFRkFVTFQgQ0hBUlNFVD11dGY4IENPTExBVEU9dXRmOF9zbG92ZW5pYW5fY2kgQ09NTUVOVD0nSW5nZXN0aW9uIGNyZWRlbnRpYWxzIHBlciBwcm9wZXJ0eSc7Cg==",
+	"2026-10-18-150000-ingest-keys.postgres.up.sql":           "Q1JFQVRFIFRBQkxFIGluZ2VzdF9rZXlzICgKIGtleV9pZCB2YXJjaGFyKDY0KSBOT1QgTlVMTCwKIHByb3BlcnR5IHZhcmNoYXIoMzIpIE5PVCBOVUxMLAogc2VjcmV0IHZhcmNoYXIoMTI4KSBOT1QgTlVMTCBERUZBVUxUICcnLAogYXBpX2tleV9oYXNoIHZhcmNoYXIoNjQpIE5PVCBOVUxMIERFRkFVTFQgJycsCiBjcmVhdGVkX2F0IHRpbWVzdGFtcCBOT1QgTlVMTCwKIGV4cGlyZXNfYXQgdGltZXN0YW1wIE5VTEwsCiBQUklNQVJZIEtFWSAoa2V5X2lkKQopOwoKQ1JFQVRFIElOREVYIGluZ2VzdF9rZXlzX3Byb3BlcnR5IE9OIGluZ2VzdF9rZXlzIChwcm9wZXJ0eSk7CgpDT01NRU5UIE9OIFRBQkxFIGluZ2VzdF9rZXlzIElTICdJbmdlc3Rpb24gY3JlZGVudGlhbHMgcGVyIHByb3BlcnR5JzsKQ09NTUVOVCBPTiBDT0xVTU4gaW5nZXN0X2tleXMua2V5X2lkIElTICdLZXkgSUQsIHNlbnQgd2l0aCBzaWduZWQgcmVxdWVzdHMnOwpDT01NRU5UIE9OIENPTFVNTiBpbmdlc3Rfa2V5cy5wcm9wZXJ0eSBJUyAnUHJvcGVydHkgbmFtZSB0aGUga2V5IGNhbiBwdXNoIHRvJzsKQ09NTUVOVCBPTiBDT0xVTU4gaW5nZXN0X2tleXMuc2VjcmV0IElTICdITUFDIHNlY3JldCwgZW1wdHkgaWYgc2lnbmluZyBpcyBkaXNhYmxlZCc7CkNPTU1FTlQgT04gQ09MVU1OIGluZ2VzdF9rZXlzLmFwaV9rZXlfaGFzaCBJUyAnSGV4IFNIQS0yNTYgb2YgdGhlIEFQSSBrZXksIGVtcHR5IGlmIGRpc2FibGVkJzsKQ09NTUVOVCBPTiBDT0xVTU4gaW5nZXN0X2tleXMuY3JlYXRlZF9hdCBJUyAnS2V5IGNyZWF0aW9uIHRpbWUnOwpDT01NRU5UIE9OIENPTFVNTiBpbmdlc3Rfa2V5cy5leHBpcmVzX2F0IElTICdLZXkgZXhwaXJ5IHRpbWUsIE5VTEwgZm9yIG5vIGV4cGlyeSc7Cg==",
+	"2026-10-18-150000-ingest-keys.sqlite.up.sql":             "Q1JFQVRFIFRBQkxFIGluZ2VzdF9rZXlzICgKIGtleV9pZCB2YXJjaGFyKDY0KSBOT1QgTlVMTCwKIHByb3BlcnR5IHZhcmNoYXIoMzIpIE5PVCBOVUxMLAogc2VjcmV0IHZhcmNoYXIoMTI4KSBOT1QgTlVMTCBERUZBVUxUICcnLAogYXBpX2tleV9oYXNoIHZhcmNoYXIoNjQpIE5PVCBOVUxMIERFRkFVTFQgJycsCiBjcmVhdGVkX2F0IGRhdGV0aW1lIE5PVCBOVUxMLAogZXhwaXJlc19hdCBkYXRldGltZSBOVUxMLAogUFJJTUFSWSBLRVkgKGtleV9pZCkKKTsKCkNSRUFURSBJTkRFWCBpbmdlc3Rfa2V5c19wcm9wZXJ0eSBPTiBpbmdlc3Rfa2V5cyAocHJvcGVydHkpOwo=",
+	"migrations.postgres.sql":                                 "Q1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgbWlncmF0aW9ucyAoCiBwcm9qZWN0IHZhcmNoYXIoMTYpIE5PVCBOVUxMLAogZmlsZW5hbWUgdmFyY2hhcigyNTUpIE5PVCBOVUxMLAogc3RhdGVtZW50X2luZGV4IGludGVnZXIgTk9UIE5VTEwsCiBzdGF0dXMgdGV4dCBOT1QgTlVMTCwKIFBSSU1BUlkgS0VZIChwcm9qZWN0LCBmaWxlbmFtZSkKKTsKCkNPTU1FTlQgT04gQ09MVU1OIG1pZ3JhdGlvbnMucHJvamVjdCBJUyAnTWljcm9zZXJ2aWNlIG9yIHByb2plY3QgbmFtZSc7CkNPTU1FTlQgT04gQ09MVU1OIG1pZ3JhdGlvbnMuZmlsZW5hbWUgSVMgJ3l5eXktbW0tZGQtSEhNTVNTLnNxbCc7CkNPTU1FTlQgT04gQ09MVU1OIG1pZ3JhdGlvbnMuc3RhdGVtZW50X2luZGV4IElTICdTdGF0ZW1lbnQgbnVtYmVyIGZyb20gU1FMIGZpbGUnOwpDT01NRU5UIE9OIENPTFVNTiBtaWdyYXRpb25zLnN0YXR1cyBJUyAnb2sgb3IgZnVsbCBlcnJvciBtZXNzYWdlJzsK",
+	"migrations.sql":                                          "Q1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgYG1pZ3JhdGlvbnNgICgKIGBwcm9qZWN0YCB2YXJjaGFyKDE2KSBOT1QgTlVMTCBDT01NRU5UICdNaWNyb3NlcnZpY2Ugb3IgcHJvamVjdCBuYW1lJywKIGBmaWxlbmFtZWAgdmFyY2hhcigyNTUpIE5PVCBOVUxMIENPTU1FTlQgJ3l5eXktbW0tZGQtSEhNTVNTLnNxbCcsCiBgc3RhdGVtZW50X2luZGV4YCBpbnQoMTEpIE5PVCBOVUxMIENPTU1FTlQgJ1N0YXRlbWVudCBudW1iZXIgZnJvbSBTUUwgZmlsZScsCiBgc3RhdHVzYCB0ZXh0IE5PVCBOVUxMIENPTU1FTlQgJ29rIG9yIGZ1bGwgZXJyb3IgbWVzc2FnZScsCiBQUklNQVJZIEtFWSAoYHByb2plY3RgLGBmaWxlbmFtZWApCikgRU5HSU5FPUlubm9EQiBERUZBVUxUIENIQVJTRVQ9dXRmODsK",
+	"migrations.sqlite.sql":                                   "Q1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgbWlncmF0aW9ucyAoCiBwcm9qZWN0IHZhcmNoYXIoMTYpIE5PVCBOVUxMLAogZmlsZW5hbWUgdmFyY2hhcigyNTUpIE5PVCBOVUxMLAogc3RhdGVtZW50X2luZGV4IGludGVnZXIgTk9UIE5VTEwsCiBzdGF0dXMgdGV4dCBOT1QgTlVMTCwKIFBSSU1BUlkgS0VZIChwcm9qZWN0LCBmaWxlbmFtZSkKKTsK",
 }
@@ -4,9 +4,13 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/titpetric/microservice/internal"
 )
 
 // NewHTTPClient produces a configured http.Client
+//
+// Requests are traced with internal.GetTracer, propagating the trace context.
 func NewHTTPClient() *http.Client {
 	timeout := 10 * time.Second
 
@@ -17,11 +21,11 @@ func NewHTTPClient() *http.Client {
 		TLSHandshakeTimeout: timeout,
 	}
 
-	return &http.Client{
+	return internal.GetTracer().WrapClient(&http.Client{
 		Timeout:   timeout,
 		Transport: transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
-	}
+	})
 }
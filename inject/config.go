@@ -0,0 +1,50 @@
+package inject
+
+import (
+	"log/slog"
+
+	"github.com/titpetric/microservice/config"
+	"github.com/titpetric/microservice/internal"
+)
+
+// Logger produces the structured logger from config
+func Logger(log config.Log) *slog.Logger {
+	return internal.NewLogger(internal.LogOptions{
+		Level:  log.Level,
+		Format: log.Format,
+	})
+}
+
+// Tracer produces the tracing backend from config
+func Tracer(tracing config.Tracing) (internal.Tracer, error) {
+	return internal.NewTracer(internal.TracingOptions{
+		Tracer:   tracing.Tracer,
+		Exporter: tracing.Exporter,
+	})
+}
+
+// Authenticator produces an *internal.Authenticator for the service rules from config
+func Authenticator(rules internal.AuthRules, auth config.Auth) (*internal.Authenticator, error) {
+	return internal.NewAuthenticator(rules, internal.AuthOptions{
+		RulesFile: auth.RulesFile,
+		JWT: internal.JWTOptions{
+			JWKSFiles: auth.JWKSFiles,
+			Issuer:    auth.Issuer,
+			Audience:  auth.Audience,
+		},
+	})
+}
+
+// Discovery produces RPC client endpoint discovery from config
+func Discovery(discovery config.Discovery) internal.Discovery {
+	return internal.Discovery{
+		Addr: discovery.Addr,
+		File: discovery.File,
+		SRV:  discovery.SRV,
+	}
+}
+
+// CORSOrigins produces the allowed CORS origins from config
+func CORSOrigins(cors config.CORS) internal.CORSOrigins {
+	return internal.CORSOrigins(cors.Origins)
+}
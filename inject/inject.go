@@ -4,12 +4,18 @@ import (
 	"github.com/google/wire"
 
 	"github.com/titpetric/microservice/client"
+	"github.com/titpetric/microservice/config"
 	"github.com/titpetric/microservice/db"
 )
 
 // Inject is the main ProviderSet for wire
 var Inject = wire.NewSet(
+	wire.FieldsOf(new(*config.Config), "Server", "Database", "Flusher", "CORS", "Log", "Ingest", "Discovery"),
 	db.Connect,
 	Sonyflake,
+	NewHTTPClient,
+	Logger,
+	CORSOrigins,
+	Discovery,
 	client.Inject,
 )
@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/twitchtv/twirp"
+)
+
+type (
+	claimsCtxKey struct{}
+)
+
+// AuthRule declares authorization for a RPC method
+type AuthRule struct {
+	// Public methods don't require a token
+	Public bool `json:"public"`
+	// Scopes must all be granted by the token
+	Scopes []string `json:"scopes"`
+}
+
+// AuthRules are keyed by `package.Service/Method`, with `*` as the default rule
+//
+// Methods without a rule, and without a default rule, require a valid token.
+type AuthRules map[string]AuthRule
+
+// Rule returns the rule for method
+func (rules AuthRules) Rule(method string) AuthRule {
+	if rule, ok := rules[method]; ok {
+		return rule
+	}
+	return rules["*"]
+}
+
+// Claims are the verified JWT claims
+type Claims map[string]interface{}
+
+// Subject returns the `sub` claim
+func (c Claims) Subject() string {
+	sub, _ := c["sub"].(string)
+	return sub
+}
+
+// Scopes returns the scopes from `scope` (space separated) or `scp` claims
+func (c Claims) Scopes() []string {
+	if scope, ok := c["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	result := []string{}
+	if scp, ok := c["scp"].([]interface{}); ok {
+		for _, scope := range scp {
+			if scope, ok := scope.(string); ok {
+				result = append(result, scope)
+			}
+		}
+	}
+	return result
+}
+
+// HasScopes reports if all scopes are granted
+func (c Claims) HasScopes(scopes ...string) bool {
+	granted := map[string]bool{}
+	for _, scope := range c.Scopes() {
+		granted[scope] = true
+	}
+	for _, scope := range scopes {
+		if !granted[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+// SetClaimsToContext sets Claims value to ctx
+func SetClaimsToContext(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+// GetClaimsFromContext gets Claims value from ctx
+func GetClaimsFromContext(ctx context.Context) Claims {
+	if claims, ok := ctx.Value(claimsCtxKey{}).(Claims); ok {
+		return claims
+	}
+	return nil
+}
+
+// Authenticator verifies JWT bearer tokens and authorizes RPC methods
+type Authenticator struct {
+	verifier *JWTVerifier
+	rules    AuthRules
+}
+
+// AuthOptions configure the Authenticator
+type AuthOptions struct {
+	// RulesFile is a JSON file with rules, added to the service rules
+	RulesFile string
+	// JWT configures token verification
+	JWT JWTOptions
+}
+
+// NewAuthenticator creates an *Authenticator for the service rules
+//
+// Rules from the JSON file in options.RulesFile are added to, or replace,
+// the service rules. Tokens are verified with NewJWTVerifier.
+func NewAuthenticator(rules AuthRules, options AuthOptions) (*Authenticator, error) {
+	verifier, err := NewJWTVerifier(options.JWT)
+	if err != nil {
+		return nil, err
+	}
+
+	result := AuthRules{}
+	for method, rule := range rules {
+		result[method] = rule
+	}
+	if filename := options.RulesFile; filename != "" {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer f.Close()
+		if err := json.NewDecoder(f).Decode(&result); err != nil {
+			return nil, errors.Wrapf(err, "error decoding %s", filename)
+		}
+	}
+
+	return &Authenticator{
+		verifier: verifier,
+		rules:    result,
+	}, nil
+}
+
+// Wrap wraps a http.Handler to authorize requests, and store token claims into the context
+func (auth *Authenticator) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule := auth.rules.Rule(authMethod(r.URL.Path))
+
+		ctx := r.Context()
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token != "" && token != r.Header.Get("Authorization") {
+			claims, err := auth.verifier.Verify(token)
+			if err != nil && !rule.Public {
+				// the failed check is logged, clients only learn the token is invalid
+				slog.WarnContext(ctx, "Invalid bearer token", "err", err, "method", authMethod(r.URL.Path))
+				writeAuthError(w, r, twirp.NewError(twirp.Unauthenticated, "invalid token"))
+				return
+			}
+			if err == nil {
+				ctx = SetClaimsToContext(ctx, claims)
+			}
+		}
+
+		if !rule.Public {
+			claims := GetClaimsFromContext(ctx)
+			if claims == nil {
+				writeAuthError(w, r, twirp.NewError(twirp.Unauthenticated, "missing bearer token"))
+				return
+			}
+			if !claims.HasScopes(rule.Scopes...) {
+				writeAuthError(w, r, twirp.NewError(twirp.PermissionDenied, "missing scope: "+strings.Join(rule.Scopes, " ")))
+				return
+			}
+		}
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authMethod returns `package.Service/Method` for Twirp, gRPC and Connect paths
+func authMethod(path string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, "/twirp"), "/")
+}
+
+// writeAuthError writes err as a gRPC status for gRPC clients, or as a twirp error
+func writeAuthError(w http.ResponseWriter, r *http.Request, err twirp.Error) {
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "application/grpc") {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Grpc-Status", strconv.Itoa(int(twirpToGRPC[err.Code()])))
+		w.Header().Set("Grpc-Message", err.Msg())
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	twirp.WriteError(w, err)
+}
@@ -0,0 +1,205 @@
+package internal
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// JWK is a JSON Web Key, RSA or symmetric (oct)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+
+	// N and E are the RSA public key
+	N string `json:"n"`
+	E string `json:"e"`
+	// K is the symmetric key
+	K string `json:"k"`
+
+	rsa    *rsa.PublicKey
+	secret []byte
+}
+
+// JWTVerifier verifies HS256 and RS256 signed tokens
+type JWTVerifier struct {
+	keys     []*JWK
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// JWTOptions configure token verification
+type JWTOptions struct {
+	// JWKSFiles are JWKS files with verification keys
+	JWKSFiles []string
+	// Issuer and Audience, if set, require matching claims
+	Issuer   string
+	Audience string
+}
+
+// NewJWTVerifier creates a *JWTVerifier, reading keys from the JWKS files
+func NewJWTVerifier(options JWTOptions) (*JWTVerifier, error) {
+	verifier := &JWTVerifier{
+		issuer:   options.Issuer,
+		audience: options.Audience,
+		leeway:   30 * time.Second,
+		now:      time.Now,
+	}
+	for _, filename := range options.JWKSFiles {
+		if err := verifier.AddJWKS(filename); err != nil {
+			return nil, err
+		}
+	}
+	return verifier, nil
+}
+
+// AddJWKS adds the keys from a JWKS file
+func (v *JWTVerifier) AddJWKS(filename string) error {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var jwks struct {
+		Keys []*JWK `json:"keys"`
+	}
+	if err := json.Unmarshal(contents, &jwks); err != nil {
+		return errors.Wrapf(err, "error decoding %s", filename)
+	}
+
+	for _, key := range jwks.Keys {
+		switch key.KeyType {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return errors.Wrapf(err, "invalid key %q in %s", key.KeyID, filename)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil {
+				return errors.Wrapf(err, "invalid key %q in %s", key.KeyID, filename)
+			}
+			key.rsa = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "oct":
+			if key.secret, err = base64.RawURLEncoding.DecodeString(key.K); err != nil {
+				return errors.Wrapf(err, "invalid key %q in %s", key.KeyID, filename)
+			}
+		default:
+			return errors.Errorf("unsupported key type %q for key %q in %s", key.KeyType, key.KeyID, filename)
+		}
+		v.keys = append(v.keys, key)
+	}
+	return nil
+}
+
+// Verify verifies the token signature and claims
+func (v *JWTVerifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "malformed token header")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed token signature")
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range v.keys {
+		if header.KeyID != "" && key.KeyID != header.KeyID {
+			continue
+		}
+		if key.verify(header.Algorithm, signed, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("invalid token signature")
+	}
+
+	claims := Claims{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "malformed token claims")
+	}
+	return claims, v.verifyClaims(claims)
+}
+
+func (v *JWTVerifier) verifyClaims(claims Claims) error {
+	now := v.now()
+	if exp, ok := claims["exp"].(float64); ok && now.After(time.Unix(int64(exp), 0).Add(v.leeway)) {
+		return errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.leeway).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token not valid yet")
+	}
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return errors.New("invalid token issuer")
+	}
+	if v.audience != "" && !claimContains(claims["aud"], v.audience) {
+		return errors.New("invalid token audience")
+	}
+	return nil
+}
+
+// verify checks the signature, the algorithm must match the key type
+func (key *JWK) verify(algorithm string, signed, signature []byte) bool {
+	if key.Algorithm != "" && key.Algorithm != algorithm {
+		return false
+	}
+	switch {
+	case algorithm == "RS256" && key.rsa != nil:
+		hash := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(key.rsa, crypto.SHA256, hash[:], signature) == nil
+	case algorithm == "HS256" && key.secret != nil:
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), signature)
+	}
+	return false
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(decoded, v)
+}
+
+// claimContains checks a string or list claim for value
+func claimContains(claim interface{}, value string) bool {
+	switch claim := claim.(type) {
+	case string:
+		return claim == value
+	case []interface{}:
+		for _, v := range claim {
+			if v == value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package internal_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/titpetric/microservice/internal"
+)
+
+func signTestToken(t *testing.T, header, claims map[string]interface{}, sign func([]byte) []byte) string {
+	encode := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := encode(header) + "." + encode(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func TestAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("hmac-secret")
+
+	jwks := map[string]interface{}{
+		"keys": []map[string]interface{}{
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "oct",
+				"kid": "hmac-1",
+				"k":   base64.RawURLEncoding.EncodeToString(secret),
+			},
+		},
+	}
+	dir, err := ioutil.TempDir("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "jwks.json")
+	contents, _ := json.Marshal(jwks)
+	ioutil.WriteFile(filename, contents, 0644)
+
+	auth, err := internal.NewAuthenticator(internal.AuthRules{
+		"stats.StatsService/Push": {Public: true},
+		"*":                       {Scopes: []string{"stats:admin"}},
+	}, internal.AuthOptions{
+		JWT: internal.JWTOptions{JWKSFiles: []string{filename}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	var subject string
+	handler := auth.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject = internal.GetClaimsFromContext(r.Context()).Subject()
+	}))
+
+	rs256 := func(claims map[string]interface{}) string {
+		return signTestToken(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}, claims, func(signed []byte) []byte {
+			hash := sha256.Sum256(signed)
+			signature, _ := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, hash[:])
+			return signature
+		})
+	}
+	hs256 := func(claims map[string]interface{}) string {
+		return signTestToken(t, map[string]interface{}{"alg": "HS256", "kid": "hmac-1"}, claims, func(signed []byte) []byte {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(signed)
+			return mac.Sum(nil)
+		})
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+	admin := map[string]interface{}{"sub": "admin", "scope": "stats:admin", "exp": exp}
+	user := map[string]interface{}{"sub": "user", "exp": exp}
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"public", "/twirp/stats.StatsService/Push", "", http.StatusOK},
+		{"public with invalid token", "/twirp/stats.StatsService/Push", "invalid", http.StatusOK},
+		{"missing token", "/twirp/stats.StatsService/Query", "", http.StatusUnauthorized},
+		{"missing scope", "/twirp/stats.StatsService/Query", rs256(user), http.StatusForbidden},
+		{"rs256", "/twirp/stats.StatsService/Query", rs256(admin), http.StatusOK},
+		{"hs256", "/stats.StatsService/Query", hs256(admin), http.StatusOK},
+		{"expired", "/twirp/stats.StatsService/Query", rs256(map[string]interface{}{"scope": "stats:admin", "exp": time.Now().Add(-time.Hour).Unix()}), http.StatusUnauthorized},
+		{"alg none", "/twirp/stats.StatsService/Query", signTestToken(t, map[string]interface{}{"alg": "none"}, admin, func([]byte) []byte { return nil }), http.StatusUnauthorized},
+		{"hs256 with rsa key", "/twirp/stats.StatsService/Query", signTestToken(t, map[string]interface{}{"alg": "HS256", "kid": "rsa-1"}, admin, func(signed []byte) []byte {
+			mac := hmac.New(sha256.New, rsaKey.N.Bytes())
+			mac.Write(signed)
+			return mac.Sum(nil)
+		}), http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		subject = ""
+		req := httptest.NewRequest("POST", test.path, nil)
+		if test.token != "" {
+			req.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d: %s", test.name, test.status, w.Code, w.Body.String())
+		}
+		if test.status == http.StatusUnauthorized && test.token != "" && !strings.Contains(w.Body.String(), `"msg":"invalid token"`) {
+			t.Errorf("%s: expected generic invalid token message, got %s", test.name, w.Body.String())
+		}
+		if test.status == http.StatusOK && test.token != "" && test.token != "invalid" && subject != "admin" {
+			t.Errorf("%s: expected claims in context, got subject %q", test.name, subject)
+		}
+	}
+
+	// gRPC clients get a gRPC status
+	req := httptest.NewRequest("POST", "/stats.StatsService/Query", nil)
+	req.Header.Set("Content-Type", "application/grpc")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Grpc-Status"); got != "16" {
+		t.Errorf("Expected grpc-status 16, got %q", got)
+	}
+}
@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"net/http"
+)
+
+// Balancer is a HTTPClient spreading requests over resolved endpoints
+//
+// Requests are sent to endpoints in round-robin order. Endpoints failing
+// several requests in a row are ejected for a while. If all endpoints are
+// ejected, requests are spread over all of them. If no endpoints resolve,
+// requests are sent to their original URL.
+//
+// Endpoints are kept in an immutable snapshot, so requests don't wait on
+// each other. The first request resolves endpoints, later requests refresh
+// a stale snapshot in the background.
+type Balancer struct {
+	client   HTTPClient
+	resolver Resolver
+
+	// refresh is the interval between endpoint resolves
+	refresh time.Duration
+	// resolveTimeout limits each resolve, independent of requests
+	resolveTimeout time.Duration
+	// resolving serializes resolves
+	resolving sync.Mutex
+	// refreshing is set while a background refresh is pending
+	refreshing *atomic.Bool
+	// resolvedAt is the time of the last resolve, in unix nanoseconds
+	resolvedAt *atomic.Int64
+
+	// ejectAfter consecutive failures eject an endpoint for ejectFor
+	ejectAfter int32
+	ejectFor   time.Duration
+
+	// endpoints holds the current endpointSet, replaced on resolve
+	endpoints atomic.Value
+	next      *atomic.Uint32
+}
+
+// endpointSet is a resolved snapshot of endpoints
+type endpointSet []*endpoint
+
+type endpoint struct {
+	url       *url.URL
+	failures  *atomic.Int32
+	ejectedAt *atomic.Int64
+}
+
+// NewBalancer creates a *Balancer
+func NewBalancer(resolver Resolver, client HTTPClient) *Balancer {
+	return &Balancer{
+		client:         client,
+		resolver:       resolver,
+		refresh:        30 * time.Second,
+		resolveTimeout: 5 * time.Second,
+		refreshing:     atomic.NewBool(false),
+		resolvedAt:     atomic.NewInt64(0),
+		ejectAfter:     3,
+		ejectFor:       30 * time.Second,
+		next:           atomic.NewUint32(0),
+	}
+}
+
+// Do performs the request against the next healthy endpoint
+func (b *Balancer) Do(req *http.Request) (*http.Response, error) {
+	target := b.pick(req.Context())
+	if target == nil {
+		return b.client.Do(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = target.url.Scheme
+	req.URL.Host = target.url.Host
+	req.Host = target.url.Host
+
+	resp, err := b.client.Do(req)
+	b.record(target, err == nil && resp.StatusCode < http.StatusBadGateway)
+	return resp, err
+}
+
+// pick returns the next endpoint, or nil when none are resolved
+func (b *Balancer) pick(ctx context.Context) *endpoint {
+	endpoints := b.current(ctx)
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for range endpoints {
+		target := endpoints[int(b.next.Inc()-1)%len(endpoints)]
+		if target.failures.Load() < b.ejectAfter || now.Sub(time.Unix(0, target.ejectedAt.Load())) > b.ejectFor {
+			return target
+		}
+	}
+	// all endpoints are ejected
+	return endpoints[int(b.next.Inc()-1)%len(endpoints)]
+}
+
+// current returns the endpoint snapshot, resolving or refreshing it as needed
+func (b *Balancer) current(ctx context.Context) endpointSet {
+	endpoints, ok := b.endpoints.Load().(endpointSet)
+	if !ok {
+		// the first requests wait for endpoints to resolve once
+		b.resolving.Lock()
+		if endpoints, ok = b.endpoints.Load().(endpointSet); !ok {
+			endpoints = b.resolve(ctx)
+		}
+		b.resolving.Unlock()
+		return endpoints
+	}
+
+	if time.Since(time.Unix(0, b.resolvedAt.Load())) > b.refresh && b.refreshing.CAS(false, true) {
+		go func() {
+			defer b.refreshing.Store(false)
+			b.resolving.Lock()
+			defer b.resolving.Unlock()
+			b.resolve(ctx)
+		}()
+	}
+	return endpoints
+}
+
+// resolve replaces the endpoint snapshot, keeping the health of known endpoints
+//
+// The resolve isn't bound to the request ctx, so a canceled request doesn't
+// fail the resolve for others. On error, the previous endpoints are kept.
+// The caller must hold b.resolving.
+func (b *Balancer) resolve(ctx context.Context) endpointSet {
+	previous, _ := b.endpoints.Load().(endpointSet)
+	b.resolvedAt.Store(time.Now().UnixNano())
+
+	resolveCtx, cancel := context.WithTimeout(context.Background(), b.resolveTimeout)
+	defer cancel()
+
+	addrs, err := b.resolver.Resolve(resolveCtx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error resolving endpoints", "err", err)
+		if previous == nil {
+			previous = endpointSet{}
+		}
+		b.endpoints.Store(previous)
+		return previous
+	}
+
+	known := make(map[string]*endpoint, len(previous))
+	for _, target := range previous {
+		known[target.url.String()] = target
+	}
+
+	endpoints := make(endpointSet, 0, len(addrs))
+	for _, addr := range addrs {
+		u, err := url.Parse(addr)
+		if err != nil || u.Host == "" {
+			slog.WarnContext(ctx, "Invalid endpoint", "addr", addr)
+			continue
+		}
+		if target, ok := known[u.String()]; ok {
+			endpoints = append(endpoints, target)
+			continue
+		}
+		endpoints = append(endpoints, &endpoint{
+			url:       u,
+			failures:  atomic.NewInt32(0),
+			ejectedAt: atomic.NewInt64(0),
+		})
+	}
+	b.endpoints.Store(endpoints)
+	return endpoints
+}
+
+func (b *Balancer) record(target *endpoint, success bool) {
+	if success {
+		target.failures.Store(0)
+		return
+	}
+	if target.failures.Inc() >= b.ejectAfter {
+		target.ejectedAt.Store(time.Now().UnixNano())
+	}
+}
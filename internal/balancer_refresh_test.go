@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"go.uber.org/atomic"
+)
+
+// blockingResolver resolves addrs, blocking calls after the first until release is closed
+type blockingResolver struct {
+	addrs   []string
+	calls   *atomic.Int32
+	release chan struct{}
+	err     chan error
+}
+
+func (r *blockingResolver) Resolve(ctx context.Context) ([]string, error) {
+	if r.calls.Inc() > 1 {
+		<-r.release
+	}
+	r.err <- ctx.Err()
+	return r.addrs, nil
+}
+
+func TestBalancerRefresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	resolver := &blockingResolver{
+		addrs:   []string{server.URL},
+		calls:   atomic.NewInt32(0),
+		release: make(chan struct{}),
+		err:     make(chan error, 2),
+	}
+	balancer := NewBalancer(resolver, http.DefaultClient)
+	balancer.refresh = 0
+
+	// the first resolve isn't bound to the request context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if target := balancer.pick(ctx); target == nil || target.url.Host != server.Listener.Addr().String() {
+		t.Fatalf("Expected resolved endpoint, got %+v", target)
+	}
+	if err := <-resolver.err; err != nil {
+		t.Fatalf("Expected resolve with a live context, got %+v", err)
+	}
+
+	// requests don't wait for a blocked refresh, which runs only once
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			req, _ := http.NewRequest("POST", "http://stats.service:3000/twirp/stats.StatsService/Push", nil)
+			resp, err := balancer.Do(req)
+			if err != nil {
+				t.Errorf("Unexpected error: %+v", err)
+				return
+			}
+			resp.Body.Close()
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Requests blocked on endpoint refresh")
+	}
+	if calls := resolver.calls.Load(); calls > 2 {
+		t.Errorf("Expected a single background refresh, got %d resolves", calls)
+	}
+
+	close(resolver.release)
+	if err := <-resolver.err; err != nil {
+		t.Errorf("Expected refresh with a live context, got %+v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package internal_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"go.uber.org/atomic"
+
+	"github.com/titpetric/microservice/internal"
+)
+
+func TestNewResolver(t *testing.T) {
+	discovery := internal.Discovery{
+		Addr: map[string][]string{"stats": {"http://a:3000", " http://b:3000"}},
+	}
+
+	addrs, err := internal.NewResolver("stats", discovery).Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if want := []string{"http://a:3000", "http://b:3000"}; !reflect.DeepEqual(addrs, want) {
+		t.Errorf("Unexpected endpoints %v, expected %v", addrs, want)
+	}
+
+	dir, err := ioutil.TempDir("", "services")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := path.Join(dir, "services.json")
+	ioutil.WriteFile(filename, []byte(`{"other-service": ["http://c:3000"]}`), 0644)
+	discovery.File = filename
+
+	for _, service := range []string{"other-service", "OTHER_SERVICE"} {
+		addrs, err = internal.NewResolver(service, discovery).Resolve(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if want := []string{"http://c:3000"}; !reflect.DeepEqual(addrs, want) {
+			t.Errorf("Unexpected endpoints for %s %v, expected %v", service, addrs, want)
+		}
+	}
+
+	// services missing from the file fall back to SRV records
+	addrs, err = internal.NewResolver("missing", discovery).Resolve(context.Background())
+	if err != nil || len(addrs) != 0 {
+		t.Errorf("Expected no endpoints for unknown service, got %v, %+v", addrs, err)
+	}
+	discovery.SRV = map[string]string{"missing": "_missing._tcp.invalid"}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := internal.NewResolver("missing", discovery).Resolve(ctx); err == nil {
+		t.Errorf("Expected SRV lookup error for service missing from the file")
+	}
+}
+
+func TestBalancer(t *testing.T) {
+	healthy, unhealthy := atomic.NewInt32(0), atomic.NewInt32(0)
+	healthyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		healthy.Inc()
+	}))
+	defer healthyServer.Close()
+	unhealthyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		unhealthy.Inc()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthyServer.Close()
+
+	resolver := internal.StaticResolver{healthyServer.URL, unhealthyServer.URL}
+	balancer := internal.NewBalancer(resolver, http.DefaultClient)
+	for i := 0; i < 20; i++ {
+		req, _ := http.NewRequest("POST", "http://stats.service:3000/twirp/stats.StatsService/Push", nil)
+		resp, err := balancer.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// unhealthy endpoint is ejected after 3 failures
+	if unhealthy.Load() != 3 || healthy.Load() != 17 {
+		t.Errorf("Unexpected distribution, healthy=%d unhealthy=%d", healthy.Load(), unhealthy.Load())
+	}
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// Breaker is a consecutive failure circuit breaker
+//
+// After `threshold` consecutive failures the breaker opens, rejecting
+// requests until `timeout` passes. It then lets a single trial request
+// through, closing on success or opening again on failure.
+type Breaker struct {
+	sync.Mutex
+
+	threshold int
+	timeout   time.Duration
+
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// NewBreaker creates a *Breaker, a threshold of 0 disables it
+func NewBreaker(threshold int, timeout time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		timeout:   timeout,
+	}
+}
+
+// Allow reports if a request may be performed
+func (b *Breaker) Allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.trial || time.Since(b.openedAt) < b.timeout {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// Record records the result of an allowed request
+func (b *Breaker) Record(success bool) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.trial = false
+	if success {
+		b.failures = 0
+		return
+	}
+	if b.failures++; b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
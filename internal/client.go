@@ -0,0 +1,343 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"path"
+	"strconv"
+	"time"
+
+	"net/http"
+
+	"github.com/twitchtv/twirp"
+)
+
+// HTTPClient is the interface used by generated twirp clients
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOptions configure RPC clients
+type ClientOptions struct {
+	// HTTPClient performs the requests
+	HTTPClient HTTPClient
+	// Resolver resolves service endpoints to balance requests over
+	Resolver Resolver
+	// JSON selects JSON encoding instead of protobuf
+	JSON bool
+	// Timeout limits each individual attempt
+	Timeout time.Duration
+
+	// Retries is the number of retries after the first attempt
+	Retries int
+	// Backoff is the initial delay between retries, doubled on every retry
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// Idempotent lists methods which are safe to retry after they may have run
+	Idempotent map[string]bool
+
+	// BearerToken is sent as the Authorization header
+	BearerToken string
+	// APIKey is sent with every request
+	APIKey string
+	// SigningKeyID and SigningSecret sign every request
+	SigningKeyID  string
+	SigningSecret string
+
+	// BreakerThreshold is the number of consecutive failures opening the breaker
+	BreakerThreshold int
+	// BreakerTimeout is the time before an open breaker allows a trial request
+	BreakerTimeout time.Duration
+}
+
+// ClientOption modifies ClientOptions
+type ClientOption func(*ClientOptions)
+
+// NewClientOptions creates ClientOptions with defaults and applies options
+func NewClientOptions(options ...ClientOption) *ClientOptions {
+	opts := &ClientOptions{
+		HTTPClient:       GetTracer().WrapClient(http.DefaultClient),
+		Timeout:          5 * time.Second,
+		Retries:          3,
+		Backoff:          50 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		Idempotent:       map[string]bool{},
+		BreakerThreshold: 5,
+		BreakerTimeout:   10 * time.Second,
+	}
+	for _, option := range options {
+		option(opts)
+	}
+	return opts
+}
+
+// WithHTTPClient sets the HTTP client performing requests
+func WithHTTPClient(client HTTPClient) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.HTTPClient = client
+	}
+}
+
+// WithDiscovery balances requests over endpoints resolved by NewResolver(service, discovery)
+func WithDiscovery(service string, discovery Discovery) ClientOption {
+	return WithResolver(NewResolver(service, discovery))
+}
+
+// WithResolver balances requests over endpoints resolved by resolver
+func WithResolver(resolver Resolver) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.Resolver = resolver
+	}
+}
+
+// WithBearerToken sends a JWT bearer token with requests
+func WithBearerToken(token string) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.BearerToken = token
+	}
+}
+
+// WithAPIKey sends an ingestion API key with requests
+func WithAPIKey(key string) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.APIKey = key
+	}
+}
+
+// WithSigningKey signs requests with an ingestion key, see Credentials
+func WithSigningKey(keyID, secret string) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.SigningKeyID = keyID
+		opts.SigningSecret = secret
+	}
+}
+
+// WithJSON selects JSON encoding instead of protobuf
+func WithJSON() ClientOption {
+	return func(opts *ClientOptions) {
+		opts.JSON = true
+	}
+}
+
+// WithTimeout sets the timeout of each attempt, 0 disables it
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.Timeout = timeout
+	}
+}
+
+// WithRetries sets the number of retries and the exponential backoff range
+func WithRetries(retries int, backoff, maxBackoff time.Duration) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.Retries = retries
+		opts.Backoff = backoff
+		opts.MaxBackoff = maxBackoff
+	}
+}
+
+// WithIdempotent marks methods as safe to retry on any retryable error
+func WithIdempotent(methods ...string) ClientOption {
+	return func(opts *ClientOptions) {
+		for _, method := range methods {
+			opts.Idempotent[method] = true
+		}
+	}
+}
+
+// WithCircuitBreaker opens the breaker after `threshold` consecutive failures, 0 disables it
+func WithCircuitBreaker(threshold int, timeout time.Duration) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.BreakerThreshold = threshold
+		opts.BreakerTimeout = timeout
+	}
+}
+
+// Client is a HTTPClient with timeouts, retries and a circuit breaker
+type Client struct {
+	client  HTTPClient
+	options *ClientOptions
+	breaker *Breaker
+}
+
+// NewClient creates a *Client
+func NewClient(options *ClientOptions) *Client {
+	client := options.HTTPClient
+	if options.Resolver != nil {
+		client = NewBalancer(options.Resolver, client)
+	}
+	return &Client{
+		client:  client,
+		options: options,
+		breaker: NewBreaker(options.BreakerThreshold, options.BreakerTimeout),
+	}
+}
+
+// Do performs the request, retrying failed attempts
+func (client *Client) Do(req *http.Request) (*http.Response, error) {
+	idempotent := client.options.Idempotent[path.Base(req.URL.Path)]
+	backoff := client.options.Backoff
+
+	for attempt := 0; ; attempt++ {
+		if !client.breaker.Allow() {
+			return twirpErrorResponse(req, twirp.NewError(twirp.Unavailable, "circuit breaker is open")), nil
+		}
+
+		resp, err := client.do(req)
+		retry, failed := retryable(resp, err, idempotent)
+		client.breaker.Record(!failed)
+		if !retry || attempt >= client.options.Retries || req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		// full jitter exponential backoff
+		select {
+		case <-time.After(time.Duration(rand.Int63n(int64(backoff) + 1))):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if backoff *= 2; backoff > client.options.MaxBackoff {
+			backoff = client.options.MaxBackoff
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// do performs a single attempt, buffering error responses for inspection
+func (client *Client) do(req *http.Request) (*http.Response, error) {
+	cancel := func() {}
+	if client.options.Timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), client.options.Timeout)
+		req = req.WithContext(ctx)
+	}
+
+	if err := client.sign(req); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp, err := client.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		// the timeout covers reading the response body
+		resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	}
+
+	defer cancel()
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// sign adds credentials to the request
+func (client *Client) sign(req *http.Request) error {
+	if client.options.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+client.options.BearerToken)
+	}
+	if client.options.APIKey != "" {
+		req.Header.Set("X-Api-Key", client.options.APIKey)
+	}
+	if client.options.SigningKeyID == "" {
+		return nil
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		if body, err = ioutil.ReadAll(reader); err != nil {
+			return err
+		}
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("X-Signature-Key", client.options.SigningKeyID)
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+	req.Header.Set("X-Signature", Sign(client.options.SigningSecret, req.Method, req.URL, timestamp, body))
+	return nil
+}
+
+// retryable reports if an attempt can be retried, and if it counts as a failure
+func retryable(resp *http.Response, err error, idempotent bool) (retry bool, failed bool) {
+	if err != nil {
+		// the request never reached the server if dialing failed
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true, true
+		}
+		return idempotent, true
+	}
+	if resp.StatusCode == http.StatusOK {
+		return false, false
+	}
+
+	var twerr struct {
+		Code twirp.ErrorCode `json:"code"`
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	json.Unmarshal(body, &twerr)
+
+	switch twerr.Code {
+	case twirp.Unavailable, twirp.ResourceExhausted:
+		// the request was rejected before it was processed
+		return true, true
+	case twirp.DeadlineExceeded, twirp.Aborted, twirp.Internal, twirp.Unknown, "":
+		return idempotent, true
+	}
+	return false, false
+}
+
+// twirpErrorResponse produces a twirp error response without performing a request
+func twirpErrorResponse(req *http.Request, twerr twirp.Error) *http.Response {
+	body, _ := json.Marshal(map[string]string{
+		"code": string(twerr.Code()),
+		"msg":  twerr.Msg(),
+	})
+	return &http.Response{
+		Status:     http.StatusText(twirp.ServerHTTPStatusFromErrorCode(twerr.Code())),
+		StatusCode: twirp.ServerHTTPStatusFromErrorCode(twerr.Code()),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// cancelBody releases the attempt context when the body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (body *cancelBody) Close() error {
+	defer body.cancel()
+	return body.ReadCloser.Close()
+}
@@ -0,0 +1,144 @@
+package internal_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/twitchtv/twirp"
+	"go.elastic.co/apm"
+	"go.elastic.co/apm/module/apmhttp"
+	"go.uber.org/atomic"
+
+	"github.com/titpetric/microservice/internal"
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+func TestClientRetries(t *testing.T) {
+	svc := new(testService)
+	handler := stats.NewStatsServiceServer(svc, nil)
+
+	// fail the first two requests with twirp.Unavailable
+	requests := atomic.NewInt32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Inc() <= 2 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code":"unavailable","msg":"try again"}`))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	opts := internal.NewClientOptions(internal.WithRetries(3, time.Millisecond, 5*time.Millisecond))
+	client := stats.NewStatsServiceProtobufClient(server.URL, internal.NewClient(opts))
+	if _, err := client.Push(context.Background(), &stats.PushRequest{Property: "news", Section: 1, Id: 1}); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests.Load())
+	}
+}
+
+func TestClientBreaker(t *testing.T) {
+	requests := atomic.NewInt32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Inc()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"code":"unavailable","msg":"down"}`))
+	}))
+	defer server.Close()
+
+	opts := internal.NewClientOptions(
+		internal.WithJSON(),
+		internal.WithRetries(0, 0, 0),
+		internal.WithCircuitBreaker(2, time.Hour),
+	)
+	client := stats.NewStatsServiceJSONClient(server.URL, internal.NewClient(opts))
+	for i := 0; i < 5; i++ {
+		_, err := client.Push(context.Background(), &stats.PushRequest{})
+		if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.Unavailable {
+			t.Fatalf("Expected twirp.Unavailable, got %+v", err)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected breaker to stop requests after 2, got %d", requests.Load())
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	breaker := internal.NewBreaker(1, 10*time.Millisecond)
+	if !breaker.Allow() {
+		t.Fatal("Expected closed breaker")
+	}
+	breaker.Record(false)
+	if breaker.Allow() {
+		t.Fatal("Expected open breaker")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !breaker.Allow() {
+		t.Fatal("Expected trial request")
+	}
+	if breaker.Allow() {
+		t.Fatal("Expected a single trial request")
+	}
+	breaker.Record(true)
+	if !breaker.Allow() {
+		t.Fatal("Expected closed breaker after trial")
+	}
+}
+
+func TestClientTracePropagation(t *testing.T) {
+	var trace apm.TraceID
+	server := httptest.NewServer(apmhttp.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tx := apm.TransactionFromContext(r.Context()); tx != nil {
+			trace = tx.TraceContext().Trace
+		}
+	})))
+	defer server.Close()
+
+	tx := apm.DefaultTracer.StartTransaction("test", "request")
+	defer tx.End()
+
+	req, _ := http.NewRequest("POST", server.URL, nil)
+	req = req.WithContext(apm.ContextWithTransaction(context.Background(), tx))
+	resp, err := internal.NewClient(internal.NewClientOptions()).Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	resp.Body.Close()
+
+	if trace != tx.TraceContext().Trace {
+		t.Errorf("Expected trace %s, got %s", tx.TraceContext().Trace, trace)
+	}
+}
+
+func TestClientSigning(t *testing.T) {
+	var credentials internal.Credentials
+	server := httptest.NewServer(internal.WrapWithCredentials(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credentials = internal.GetCredentialsFromContext(r.Context())
+	})))
+	defer server.Close()
+
+	opts := internal.NewClientOptions(internal.WithSigningKey("key-1", "s3cret"))
+	req, _ := http.NewRequest("POST", server.URL, strings.NewReader(`{"property":"news"}`))
+	resp, err := internal.NewClient(opts).Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	resp.Body.Close()
+
+	if credentials.KeyID != "key-1" || !credentials.VerifySignature("s3cret") {
+		t.Errorf("Expected valid signature, got %+v", credentials)
+	}
+	if credentials.VerifySignature("guess") {
+		t.Errorf("Expected invalid signature with wrong secret")
+	}
+}
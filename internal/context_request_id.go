@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"context"
+)
+
+type (
+	requestIDCtxKey struct{}
+)
+
+// SetRequestIDToContext sets request ID value to ctx
+func SetRequestIDToContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// GetRequestIDFromContext gets request ID value from ctx
+func GetRequestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDCtxKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
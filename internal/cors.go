@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"net/http"
+)
+
+type (
+	originCtxKey struct{}
+)
+
+// corsAllowHeaders are the request headers browsers may send
+var corsAllowHeaders = strings.Join([]string{
+	"Content-Type",
+	"Authorization",
+	"X-Request-ID",
+	"X-Api-Key",
+	"X-Grpc-Web",
+	"X-User-Agent",
+	"Grpc-Timeout",
+	"Connect-Protocol-Version",
+	"Connect-Timeout-Ms",
+}, ", ")
+
+// corsExposeHeaders are the response headers readable by browsers
+var corsExposeHeaders = strings.Join([]string{
+	"X-Request-ID",
+	"Grpc-Status",
+	"Grpc-Message",
+}, ", ")
+
+// CORSOrigins are allowed browser origins per property, `*` is the default property
+//
+// Origins match exactly, `*` allows any origin and `https://*.example.com`
+// allows subdomains. Without configured origins, all origins are allowed.
+type CORSOrigins map[string][]string
+
+// Allowed reports if origin may send requests for property
+//
+// Without configured origins, all origins are allowed.
+func (o CORSOrigins) Allowed(property, origin string) bool {
+	if len(o) == 0 {
+		return true
+	}
+	patterns, ok := o[property]
+	if !ok {
+		patterns = o["*"]
+	}
+	return matchOrigin(patterns, origin)
+}
+
+// AllowedAny reports if origin may send requests for any property
+//
+// Without configured origins, all origins are allowed.
+func (o CORSOrigins) AllowedAny(origin string) bool {
+	if len(o) == 0 {
+		return true
+	}
+	for _, patterns := range o {
+		if matchOrigin(patterns, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchOrigin(patterns []string, origin string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+		if idx := strings.Index(pattern, "*."); idx >= 0 {
+			prefix, suffix := pattern[:idx], pattern[idx+1:]
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) && len(origin) > len(prefix)+len(suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetOriginToContext sets Origin value to ctx
+func SetOriginToContext(ctx context.Context, origin string) context.Context {
+	return context.WithValue(ctx, originCtxKey{}, origin)
+}
+
+// GetOriginFromContext gets Origin value from ctx
+func GetOriginFromContext(ctx context.Context) string {
+	if origin, ok := ctx.Value(originCtxKey{}).(string); ok {
+		return origin
+	}
+	return ""
+}
+
+// WrapWithCORS wraps a http.Handler to answer preflight requests and send CORS headers
+//
+// The request Origin is stored in the context, so services can check it
+// against the property with CORSOrigins.Allowed. Without configured
+// origins, responses allow any origin with `*`.
+func WrapWithCORS(h http.Handler, origins CORSOrigins) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		allowed := origins.AllowedAny(origin)
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		if allowed {
+			if len(origins) == 0 {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			header.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if allowed {
+				header.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				header.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+				header.Set("Access-Control-Max-Age", "600")
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		ctx := r.Context()
+		ctx = SetOriginToContext(ctx, origin)
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,102 @@
+package internal_test
+
+import (
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/titpetric/microservice/internal"
+)
+
+func TestCORSOrigins(t *testing.T) {
+	origins := internal.CORSOrigins{
+		"news":  {"https://news.example.com", "https://*.news.example.com"},
+		"*":     {"https://www.example.com"},
+		"games": {"*"},
+	}
+
+	cases := []struct {
+		property, origin string
+		allowed          bool
+	}{
+		{"news", "https://news.example.com", true},
+		{"news", "https://m.news.example.com", true},
+		{"news", "https://.news.example.com", false},
+		{"news", "https://sport.example.com", false},
+		{"news", "https://www.example.com", false},
+		{"sport", "https://www.example.com", true},
+		{"sport", "https://news.example.com", false},
+		{"games", "https://anything.test", true},
+	}
+	for _, c := range cases {
+		if got := origins.Allowed(c.property, c.origin); got != c.allowed {
+			t.Errorf("Allowed(%q, %q) = %v, expected %v", c.property, c.origin, got, c.allowed)
+		}
+	}
+
+	if !(internal.CORSOrigins{}).Allowed("news", "https://any.test") || !(internal.CORSOrigins{}).AllowedAny("https://any.test") {
+		t.Errorf("Expected any origin allowed without configuration")
+	}
+}
+
+func TestWrapWithCORSUnconfigured(t *testing.T) {
+	handler := internal.WrapWithCORS(http.NotFoundHandler(), nil)
+
+	req := httptest.NewRequest("OPTIONS", "/twirp/stats.StatsService/Push", nil)
+	req.Header.Set("Origin", "https://any.test")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("Expected any origin allowed without configuration, got %v", w.Header())
+	}
+}
+
+func TestWrapWithCORS(t *testing.T) {
+	origins := internal.CORSOrigins{
+		"news": {"https://news.example.com"},
+	}
+
+	var called bool
+	var origin string
+	handler := internal.WrapWithCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		origin = internal.GetOriginFromContext(r.Context())
+	}), origins)
+
+	// preflight is answered without calling the handler
+	req := httptest.NewRequest("OPTIONS", "/twirp/stats.StatsService/Push", nil)
+	req.Header.Set("Origin", "https://news.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if called || w.Code != http.StatusNoContent {
+		t.Errorf("Expected preflight response, got called=%v, status %d", called, w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://news.example.com" || w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("Expected CORS headers on preflight, got %v", w.Header())
+	}
+
+	// preflight from unknown origins gets no CORS headers
+	req = httptest.NewRequest("OPTIONS", "/twirp/stats.StatsService/Push", nil)
+	req.Header.Set("Origin", "https://evil.test")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected no CORS headers for unknown origin, got %v", w.Header())
+	}
+
+	// requests pass the origin in the context
+	req = httptest.NewRequest("POST", "/twirp/stats.StatsService/Push", nil)
+	req.Header.Set("Origin", "https://news.example.com")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if !called || origin != "https://news.example.com" {
+		t.Errorf("Expected handler with origin, got called=%v, origin %q", called, origin)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://news.example.com" || w.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Errorf("Expected CORS headers on request, got %v", w.Header())
+	}
+}
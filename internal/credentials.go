@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+
+	"net/http"
+	"net/url"
+)
+
+// maxSignedBodySize limits the body size of signed requests
+const maxSignedBodySize = 1 << 20
+
+// Query parameters for signed URLs, e.g. tracking pixels which can't send headers
+const (
+	signatureKeyParam       = "signature_key"
+	signatureTimestampParam = "signature_timestamp"
+	signatureParam          = "signature"
+)
+
+type (
+	credentialsCtxKey struct{}
+)
+
+// Credentials are ingestion credentials sent with a request
+//
+// Callers either send an API key with `X-Api-Key`, or sign requests
+// with `X-Signature-Key` (key ID), `X-Signature-Timestamp` (unix time)
+// and `X-Signature`, a hex encoded HMAC-SHA256 over the method, path,
+// canonical query, timestamp and body hash. Signed URLs carry the same
+// values in the signature_key, signature_timestamp and signature query
+// parameters, see SignURL.
+type Credentials struct {
+	APIKey string
+
+	KeyID     string
+	Timestamp string
+	Signature string
+
+	// Method, Path and Query identify the signed request, Query is canonical
+	Method string
+	Path   string
+	Query  string
+	// BodyHash is the hex encoded SHA-256 of the signed request body
+	BodyHash string
+}
+
+// Empty reports if no credentials were sent
+func (c Credentials) Empty() bool {
+	return c.APIKey == "" && c.KeyID == ""
+}
+
+// VerifySignature verifies the request signature with secret
+func (c Credentials) VerifySignature(secret string) bool {
+	if secret == "" {
+		return false
+	}
+	expected := signature(secret, c.Method, c.Path, c.Query, c.Timestamp, c.BodyHash)
+	return hmac.Equal([]byte(expected), []byte(c.Signature))
+}
+
+// Sign returns the X-Signature value for a request to u with body sent at timestamp
+func Sign(secret, method string, u *url.URL, timestamp string, body []byte) string {
+	hash := sha256.Sum256(body)
+	return signature(secret, method, canonicalPath(u), canonicalQuery(u), timestamp, hex.EncodeToString(hash[:]))
+}
+
+// SignURL returns a copy of u signed for GET requests, with credentials in the query
+func SignURL(u *url.URL, keyID, secret, timestamp string) *url.URL {
+	signed := *u
+	query := u.Query()
+	query.Set(signatureKeyParam, keyID)
+	query.Set(signatureTimestampParam, timestamp)
+	query.Set(signatureParam, Sign(secret, http.MethodGet, u, timestamp, nil))
+	signed.RawQuery = query.Encode()
+	return &signed
+}
+
+// signature is a HMAC-SHA256 over the newline separated request values
+func signature(secret, method, path, query, timestamp, bodyHash string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{method, path, query, timestamp, bodyHash}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// canonicalPath returns the escaped path, an empty path is sent as `/`
+func canonicalPath(u *url.URL) string {
+	if path := u.EscapedPath(); path != "" {
+		return path
+	}
+	return "/"
+}
+
+// canonicalQuery returns the query sorted by key, without signature parameters
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	query.Del(signatureKeyParam)
+	query.Del(signatureTimestampParam)
+	query.Del(signatureParam)
+	return query.Encode()
+}
+
+// SetCredentialsToContext sets Credentials value to ctx
+func SetCredentialsToContext(ctx context.Context, credentials Credentials) context.Context {
+	return context.WithValue(ctx, credentialsCtxKey{}, credentials)
+}
+
+// GetCredentialsFromContext gets Credentials value from ctx
+func GetCredentialsFromContext(ctx context.Context) Credentials {
+	if credentials, ok := ctx.Value(credentialsCtxKey{}).(Credentials); ok {
+		return credentials
+	}
+	return Credentials{}
+}
+
+// WrapWithCredentials wraps a http.Handler to read ingestion credentials into the context
+//
+// Credentials are verified by the service, which knows the secrets.
+// For signed requests, the body is read to compute BodyHash.
+func WrapWithCredentials(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credentials := Credentials{
+			APIKey:    r.Header.Get("X-Api-Key"),
+			KeyID:     r.Header.Get("X-Signature-Key"),
+			Timestamp: r.Header.Get("X-Signature-Timestamp"),
+			Signature: r.Header.Get("X-Signature"),
+		}
+		if credentials.KeyID == "" {
+			query := r.URL.Query()
+			credentials.KeyID = query.Get(signatureKeyParam)
+			credentials.Timestamp = query.Get(signatureTimestampParam)
+			credentials.Signature = query.Get(signatureParam)
+		}
+		if credentials.Empty() {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if credentials.KeyID != "" {
+			credentials.Method = r.Method
+			credentials.Path = canonicalPath(r.URL)
+			credentials.Query = canonicalQuery(r.URL)
+
+			var body []byte
+			if r.Body != nil {
+				var err error
+				body, err = ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodySize))
+				if err != nil {
+					http.Error(w, "Error reading request body", http.StatusRequestEntityTooLarge)
+					return
+				}
+				r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+			hash := sha256.Sum256(body)
+			credentials.BodyHash = hex.EncodeToString(hash[:])
+		}
+
+		ctx := r.Context()
+		ctx = SetCredentialsToContext(ctx, credentials)
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
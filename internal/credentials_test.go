@@ -0,0 +1,57 @@
+package internal_test
+
+import (
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/titpetric/microservice/internal"
+)
+
+func TestSignURL(t *testing.T) {
+	var credentials internal.Credentials
+	handler := internal.WrapWithCredentials(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credentials = internal.GetCredentialsFromContext(r.Context())
+	}))
+
+	verify := func(target string) bool {
+		credentials = internal.Credentials{}
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+		return credentials.VerifySignature("s3cret")
+	}
+
+	pixel, _ := url.Parse("/pixel.gif?property=news&section=1&id=10")
+	signed := internal.SignURL(pixel, "key-1", "s3cret", "1700000000")
+	if !verify(signed.String()) || credentials.KeyID != "key-1" {
+		t.Fatalf("Expected valid signature for %s, got %+v", signed, credentials)
+	}
+
+	// the signature covers the method, path and every query parameter
+	for _, change := range []func(u *url.URL){
+		func(u *url.URL) { setQuery(u, "id", "11") },
+		func(u *url.URL) { setQuery(u, "property", "sport") },
+		func(u *url.URL) { setQuery(u, "extra", "1") },
+		func(u *url.URL) { u.Path = "/beacon" },
+	} {
+		tampered := *signed
+		change(&tampered)
+		if verify(tampered.String()) {
+			t.Errorf("Expected invalid signature for %s", tampered.String())
+		}
+	}
+
+	req := httptest.NewRequest("POST", signed.String(), nil)
+	credentials = internal.Credentials{}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if credentials.VerifySignature("s3cret") {
+		t.Errorf("Expected invalid signature for a different method")
+	}
+}
+
+func setQuery(u *url.URL, key, value string) {
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+}
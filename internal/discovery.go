@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver resolves service endpoints, e.g. `http://10.0.0.1:3000`
+type Resolver interface {
+	Resolve(ctx context.Context) ([]string, error)
+}
+
+// Discovery configures service endpoints, keyed by DiscoveryName(service)
+type Discovery struct {
+	// Addr are endpoints, e.g. `http://10.0.0.1:3000`
+	Addr map[string][]string
+	// File is a JSON services config, e.g. `{"stats": ["http://..."]}`
+	File string
+	// SRV are DNS SRV record names, e.g. `_stats._tcp.service`
+	SRV map[string]string
+}
+
+// DiscoveryName normalizes a service name, e.g. `FOO_BAR` to `foo-bar`
+func DiscoveryName(service string) string {
+	return strings.ToLower(strings.Replace(service, "_", "-", -1))
+}
+
+// NewResolver creates a Resolver for service
+//
+// Endpoints are read from the first configured source in discovery:
+// Addr, the services config File, or DNS SRV records. Services not
+// listed in the File fall back to SRV records. If no source is
+// configured, the resolver returns no endpoints.
+func NewResolver(service string, discovery Discovery) Resolver {
+	name := DiscoveryName(service)
+	if addr := discovery.Addr[name]; len(addr) > 0 {
+		return StaticResolver(addr)
+	}
+
+	var fallback Resolver = StaticResolver(nil)
+	if srv := discovery.SRV[name]; srv != "" {
+		fallback = &srvResolver{name: srv}
+	}
+	if discovery.File != "" {
+		return &fileResolver{filename: discovery.File, service: name, fallback: fallback}
+	}
+	return fallback
+}
+
+// StaticResolver resolves a fixed list of endpoints
+type StaticResolver []string
+
+// Resolve returns the endpoints
+func (r StaticResolver) Resolve(context.Context) ([]string, error) {
+	result := make([]string, 0, len(r))
+	for _, addr := range r {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			result = append(result, addr)
+		}
+	}
+	return result, nil
+}
+
+// fileResolver reads endpoints from a JSON services config
+//
+// Services are matched with DiscoveryName, and resolved with fallback
+// if the file doesn't list them.
+type fileResolver struct {
+	filename string
+	service  string
+	fallback Resolver
+}
+
+func (r *fileResolver) Resolve(ctx context.Context) ([]string, error) {
+	f, err := os.Open(r.filename)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer f.Close()
+
+	services := map[string][]string{}
+	if err := json.NewDecoder(f).Decode(&services); err != nil {
+		return nil, errors.Wrapf(err, "error decoding %s", r.filename)
+	}
+	for service, addrs := range services {
+		if DiscoveryName(service) == r.service {
+			return StaticResolver(addrs).Resolve(ctx)
+		}
+	}
+	return r.fallback.Resolve(ctx)
+}
+
+// srvResolver resolves endpoints from DNS SRV records
+type srvResolver struct {
+	name string
+}
+
+func (r *srvResolver) Resolve(ctx context.Context) ([]string, error) {
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", r.name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	result := make([]string, 0, len(records))
+	for _, record := range records {
+		result = append(result, fmt.Sprintf("http://%s:%d", strings.TrimSuffix(record.Target, "."), record.Port))
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"net/http"
+
+	"go.uber.org/atomic"
+)
+
+// Drainer tracks in-flight requests for graceful shutdown
+//
+// http.Server.Shutdown doesn't wait for requests on hijacked connections
+// (h2c, gRPC), Wait blocks until those requests complete.
+type Drainer struct {
+	inFlight *atomic.Int64
+}
+
+// NewDrainer creates a *Drainer
+func NewDrainer() *Drainer {
+	return &Drainer{
+		inFlight: atomic.NewInt64(0),
+	}
+}
+
+// Wrap wraps a http.Handler to count in-flight requests
+func (d *Drainer) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.inFlight.Inc()
+		defer d.inFlight.Dec()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Wait blocks until no requests are in flight, or ctx is done
+func (d *Drainer) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for d.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+
+	"database/sql"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/twitchtv/twirp"
+)
+
+// dbError is a twirp.Error with a generic message for clients
+//
+// The database error is kept as the cause, and included in Error() for logs.
+type dbError struct {
+	twerr twirp.Error
+	cause error
+}
+
+// Code implements twirp.Error
+func (e *dbError) Code() twirp.ErrorCode {
+	return e.twerr.Code()
+}
+
+// Msg implements twirp.Error, the generic message sent to clients
+func (e *dbError) Msg() string {
+	return e.twerr.Msg()
+}
+
+// WithMeta implements twirp.Error
+func (e *dbError) WithMeta(key string, val string) twirp.Error {
+	return &dbError{
+		twerr: e.twerr.WithMeta(key, val),
+		cause: e.cause,
+	}
+}
+
+// Meta implements twirp.Error
+func (e *dbError) Meta(key string) string {
+	return e.twerr.Meta(key)
+}
+
+// MetaMap implements twirp.Error
+func (e *dbError) MetaMap() map[string]string {
+	return e.twerr.MetaMap()
+}
+
+// Error includes the database error, clients only get Msg()
+func (e *dbError) Error() string {
+	return e.twerr.Error() + ": " + e.cause.Error()
+}
+
+// Unwrap returns the database error
+func (e *dbError) Unwrap() error {
+	return e.cause
+}
+
+// Cause returns the database error, for errors.Cause
+func (e *dbError) Cause() error {
+	return e.cause
+}
+
+func newDBError(code twirp.ErrorCode, msg string, cause error) error {
+	return &dbError{
+		twerr: twirp.NewError(code, msg),
+		cause: cause,
+	}
+}
+
+// DBError maps database errors to twirp errors
+//
+// Messages are generic, so driver errors and SQL don't leak to clients.
+func DBError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if twerr, ok := err.(twirp.Error); ok {
+		return twerr
+	}
+
+	cause := errors.Cause(err)
+	switch cause {
+	case sql.ErrNoRows:
+		return newDBError(twirp.NotFound, "record not found", err)
+	case context.Canceled:
+		return newDBError(twirp.Canceled, "request canceled", err)
+	case context.DeadlineExceeded:
+		return newDBError(twirp.DeadlineExceeded, "request deadline exceeded", err)
+	case sql.ErrConnDone, sql.ErrTxDone:
+		return newDBError(twirp.Unavailable, "database unavailable", err)
+	}
+
+	switch e := cause.(type) {
+	case *mysql.MySQLError:
+		switch e.Number {
+		case 1062: // ER_DUP_ENTRY
+			return newDBError(twirp.AlreadyExists, "record already exists", err)
+		case 1205, 1213: // ER_LOCK_WAIT_TIMEOUT, ER_LOCK_DEADLOCK
+			return newDBError(twirp.Aborted, "database transaction aborted, try again", err)
+		case 1451, 1452: // ER_ROW_IS_REFERENCED_2, ER_NO_REFERENCED_ROW_2
+			return newDBError(twirp.FailedPrecondition, "database constraint violation", err)
+		}
+	case *pq.Error:
+		switch e.Code.Class() {
+		case "23":
+			if e.Code == "23505" { // unique_violation
+				return newDBError(twirp.AlreadyExists, "record already exists", err)
+			}
+			return newDBError(twirp.FailedPrecondition, "database constraint violation", err)
+		case "40": // transaction rollback, serialization failure, deadlock
+			return newDBError(twirp.Aborted, "database transaction aborted, try again", err)
+		case "08", "53", "57": // connection, resources, operator intervention
+			return newDBError(twirp.Unavailable, "database unavailable", err)
+		}
+	}
+	return newDBError(twirp.Internal, "internal database error", err)
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"database/sql"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	"github.com/twitchtv/twirp"
+)
+
+func TestDBError(t *testing.T) {
+	cases := []struct {
+		err  error
+		code twirp.ErrorCode
+	}{
+		{sql.ErrNoRows, twirp.NotFound},
+		{errors.WithStack(sql.ErrNoRows), twirp.NotFound},
+		{&mysql.MySQLError{Number: 1062}, twirp.AlreadyExists},
+		{&mysql.MySQLError{Number: 1213}, twirp.Aborted},
+		{errors.New("connection refused"), twirp.Internal},
+	}
+	for _, c := range cases {
+		twerr, ok := DBError(c.err).(twirp.Error)
+		if !ok || twerr.Code() != c.code {
+			t.Errorf("Unexpected error for %+v: %+v", c.err, DBError(c.err))
+		}
+	}
+}
+
+func TestDBErrorMessage(t *testing.T) {
+	driverErr := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; table `incoming`"}
+	err := DBError(errors.WithStack(driverErr))
+
+	twerr, ok := err.(twirp.Error)
+	if !ok || strings.Contains(twerr.Msg(), "incoming") {
+		t.Fatalf("Expected generic message, got %q", twerr.Msg())
+	}
+	if cause := errors.Cause(err); cause != driverErr {
+		t.Errorf("Expected driver error as cause, got %+v", cause)
+	}
+	if !strings.Contains(err.Error(), "Deadlock found") {
+		t.Errorf("Expected driver error in Error() for logs, got %q", err.Error())
+	}
+
+	w := httptest.NewRecorder()
+	twirp.WriteError(w, err)
+	if strings.Contains(w.Body.String(), "Deadlock") || w.Code != http.StatusConflict {
+		t.Errorf("Unexpected response for database error: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"net/http"
+
+	"github.com/twitchtv/twirp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// twirpToGRPC maps twirp error codes to gRPC codes
+var twirpToGRPC = map[twirp.ErrorCode]codes.Code{
+	twirp.Canceled:           codes.Canceled,
+	twirp.Unknown:            codes.Unknown,
+	twirp.InvalidArgument:    codes.InvalidArgument,
+	twirp.DeadlineExceeded:   codes.DeadlineExceeded,
+	twirp.NotFound:           codes.NotFound,
+	twirp.BadRoute:           codes.Unimplemented,
+	twirp.AlreadyExists:      codes.AlreadyExists,
+	twirp.PermissionDenied:   codes.PermissionDenied,
+	twirp.Unauthenticated:    codes.Unauthenticated,
+	twirp.ResourceExhausted:  codes.ResourceExhausted,
+	twirp.FailedPrecondition: codes.FailedPrecondition,
+	twirp.Aborted:            codes.Aborted,
+	twirp.OutOfRange:         codes.OutOfRange,
+	twirp.Unimplemented:      codes.Unimplemented,
+	twirp.Internal:           codes.Internal,
+	twirp.Unavailable:        codes.Unavailable,
+	twirp.DataLoss:           codes.DataLoss,
+}
+
+// NewGRPCServer provides a *grpc.Server with a tracing and error logging interceptor
+//
+// Service implementations return twirp errors, which are converted
+// into gRPC status errors with the matching code.
+func NewGRPCServer() *grpc.Server {
+	return grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx, span := GetTracer().StartSpan(ctx, info.FullMethod, "grpc")
+			defer span.End()
+
+			resp, err := handler(ctx, req)
+			if err == nil {
+				return resp, nil
+			}
+			GetTracer().CaptureError(ctx, err)
+			slog.WarnContext(ctx, "RPC error", "method", info.FullMethod, "err", err)
+			if twerr, ok := err.(twirp.Error); ok {
+				if code, ok := twirpToGRPC[twerr.Code()]; ok {
+					return nil, status.Error(code, twerr.Msg())
+				}
+			}
+			return nil, err
+		}),
+	)
+}
+
+// WrapGRPC routes gRPC requests to grpcServer and all other requests to h
+func WrapGRPC(grpcServer *grpc.Server, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// WrapH2C wraps a http.Handler to serve HTTP/2 without TLS, as used by gRPC clients
+//
+// The HTTP/2 server is registered with srv, so srv.Shutdown sends GOAWAY
+// to h2c connections. Those connections are hijacked and srv.Shutdown
+// doesn't wait for them, use a Drainer to wait for in-flight requests.
+func WrapH2C(srv *http.Server, h http.Handler) (http.Handler, error) {
+	h2s := &http2.Server{}
+	if err := http2.ConfigureServer(srv, h2s); err != nil {
+		return nil, err
+	}
+	return h2c.NewHandler(h, h2s), nil
+}
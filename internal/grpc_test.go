@@ -0,0 +1,143 @@
+package internal_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/twitchtv/twirp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/titpetric/microservice/internal"
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+type testService struct {
+	ip string
+}
+
+func (svc *testService) Push(ctx context.Context, r *stats.PushRequest) (*stats.PushResponse, error) {
+	svc.ip = internal.GetIPFromContext(ctx)
+	return new(stats.PushResponse), nil
+}
+
+func TestWrapGRPC(t *testing.T) {
+	svc := new(testService)
+	service := stats.NewStatsServiceValidator(svc)
+
+	grpcServer := internal.NewGRPCServer()
+	stats.RegisterStatsServiceServer(grpcServer, service)
+	twirpHandler := stats.NewStatsServiceServer(service, internal.NewServerHooks())
+
+	server := httptest.NewUnstartedServer(nil)
+	handler, err := internal.WrapH2C(server.Config, internal.WrapAll(internal.WrapGRPC(grpcServer, twirpHandler), nil))
+	if err != nil {
+		t.Fatalf("Unexpected error on WrapH2C: %+v", err)
+	}
+	server.Config.Handler = handler
+	server.Start()
+	defer server.Close()
+
+	// Twirp
+	twirpClient := stats.NewStatsServiceJSONClient(server.URL, http.DefaultClient)
+	if _, err := twirpClient.Push(context.Background(), &stats.PushRequest{Property: "news", Section: 1, Id: 1}); err != nil {
+		t.Fatalf("Unexpected twirp error: %+v", err)
+	}
+	if svc.ip != "127.0.0.1" {
+		t.Errorf("Unexpected IP from twirp request: %q", svc.ip)
+	}
+
+	// gRPC
+	svc.ip = ""
+	conn, err := grpc.Dial(server.Listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Unexpected error on grpc.Dial: %+v", err)
+	}
+	defer conn.Close()
+
+	grpcClient := stats.NewStatsServiceClient(conn)
+	if _, err := grpcClient.Push(context.Background(), &stats.PushRequest{Property: "news", Section: 1, Id: 1}); err != nil {
+		t.Fatalf("Unexpected grpc error: %+v", err)
+	}
+	if svc.ip != "127.0.0.1" {
+		t.Errorf("Unexpected IP from grpc request: %q", svc.ip)
+	}
+
+	_, err = grpcClient.Push(context.Background(), &stats.PushRequest{Property: "news"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %+v", err)
+	}
+
+	_, err = twirpClient.Push(context.Background(), &stats.PushRequest{Property: "news"})
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.InvalidArgument {
+		t.Errorf("Expected twirp.InvalidArgument, got %+v", err)
+	}
+}
+
+type blockingService struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (svc *blockingService) Push(ctx context.Context, r *stats.PushRequest) (*stats.PushResponse, error) {
+	close(svc.entered)
+	<-svc.release
+	return new(stats.PushResponse), nil
+}
+
+func TestWrapH2CShutdown(t *testing.T) {
+	svc := &blockingService{entered: make(chan struct{}), release: make(chan struct{})}
+	grpcServer := internal.NewGRPCServer()
+	stats.RegisterStatsServiceServer(grpcServer, svc)
+
+	drainer := internal.NewDrainer()
+	server := httptest.NewUnstartedServer(nil)
+	handler, err := internal.WrapH2C(server.Config, drainer.Wrap(internal.WrapGRPC(grpcServer, http.NotFoundHandler())))
+	if err != nil {
+		t.Fatalf("Unexpected error on WrapH2C: %+v", err)
+	}
+	server.Config.Handler = handler
+	server.Start()
+	defer server.Close()
+
+	conn, err := grpc.Dial(server.Listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Unexpected error on grpc.Dial: %+v", err)
+	}
+	defer conn.Close()
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := stats.NewStatsServiceClient(conn).Push(context.Background(), &stats.PushRequest{Property: "news", Section: 1, Id: 1})
+		result <- err
+	}()
+	<-svc.entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Config.Shutdown(ctx); err != nil {
+		t.Fatalf("Unexpected error on Shutdown: %+v", err)
+	}
+
+	// the in-flight request is drained before the gRPC server stops
+	waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	if err := drainer.Wait(waitCtx); err == nil {
+		t.Errorf("Expected Wait to block while a request is in flight")
+	}
+	waitCancel()
+
+	close(svc.release)
+	if err := drainer.Wait(ctx); err != nil {
+		t.Fatalf("Unexpected error on Wait: %+v", err)
+	}
+	grpcServer.Stop()
+
+	if err := <-result; err != nil {
+		t.Errorf("Expected in-flight request to complete, got %+v", err)
+	}
+}
@@ -0,0 +1,360 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"strconv"
+	"strings"
+
+	"net/http"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+)
+
+func init() {
+	// allows gRPC handlers to serve `application/grpc+json`
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// maxConnectBodySize limits Connect request bodies, matching the gRPC default receive size
+const maxConnectBodySize = 4 << 20
+
+// maxGRPCTimeoutValue is the largest gRPC timeout value, limited to 8 digits
+const maxGRPCTimeoutValue = 99999999
+
+// grpcTrailers are set by the gRPC server after the response body
+var grpcTrailers = map[string]bool{
+	"Grpc-Status":             true,
+	"Grpc-Message":            true,
+	"Grpc-Status-Details-Bin": true,
+}
+
+// grpcToConnect maps gRPC codes to Connect error codes and HTTP status codes
+var grpcToConnect = map[codes.Code]struct {
+	code   string
+	status int
+}{
+	codes.Canceled:           {"canceled", 499},
+	codes.Unknown:            {"unknown", http.StatusInternalServerError},
+	codes.InvalidArgument:    {"invalid_argument", http.StatusBadRequest},
+	codes.DeadlineExceeded:   {"deadline_exceeded", http.StatusGatewayTimeout},
+	codes.NotFound:           {"not_found", http.StatusNotFound},
+	codes.AlreadyExists:      {"already_exists", http.StatusConflict},
+	codes.PermissionDenied:   {"permission_denied", http.StatusForbidden},
+	codes.ResourceExhausted:  {"resource_exhausted", http.StatusTooManyRequests},
+	codes.FailedPrecondition: {"failed_precondition", http.StatusBadRequest},
+	codes.Aborted:            {"aborted", http.StatusConflict},
+	codes.OutOfRange:         {"out_of_range", http.StatusBadRequest},
+	codes.Unimplemented:      {"unimplemented", http.StatusNotImplemented},
+	codes.Internal:           {"internal", http.StatusInternalServerError},
+	codes.Unavailable:        {"unavailable", http.StatusServiceUnavailable},
+	codes.DataLoss:           {"data_loss", http.StatusInternalServerError},
+	codes.Unauthenticated:    {"unauthenticated", http.StatusUnauthorized},
+}
+
+// WrapGRPCWeb serves gRPC-Web and Connect (unary) requests with grpcServer and all other requests with h
+//
+// Both protocols are translated into gRPC requests, so they share the
+// service implementation, interceptors and the http.Handler middleware.
+// Services must be registered with grpcServer before calling WrapGRPCWeb.
+func WrapGRPCWeb(grpcServer *grpc.Server, h http.Handler) http.Handler {
+	methods := map[string]bool{}
+	for service, info := range grpcServer.GetServiceInfo() {
+		for _, method := range info.Methods {
+			if !method.IsClientStream && !method.IsServerStream {
+				methods["/"+service+"/"+method.Name] = true
+			}
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		switch {
+		case strings.HasPrefix(contentType, "application/grpc-web"):
+			serveGRPCWeb(grpcServer, w, r)
+		case r.Method == "POST" && methods[r.URL.Path] && connectMediaType(contentType) != "":
+			serveConnect(grpcServer, w, r)
+		default:
+			h.ServeHTTP(w, r)
+		}
+	})
+}
+
+// connectMediaType returns the Connect codec media type, or "" if contentType isn't one
+//
+// Parameters like `charset=utf-8` are accepted and dropped.
+func connectMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch mediaType {
+	case "application/json", "application/proto":
+		return mediaType
+	}
+	return ""
+}
+
+// grpcRequest rewrites r into a HTTP/2 gRPC request
+func grpcRequest(r *http.Request, contentType string, body io.Reader) *http.Request {
+	req := r.WithContext(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.Header = r.Header.Clone()
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	req.Body = ioutil.NopCloser(body)
+	return req
+}
+
+func serveGRPCWeb(grpcServer *grpc.Server, w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	text := strings.HasPrefix(contentType, "application/grpc-web-text")
+
+	var body io.Reader = r.Body
+	grpcContentType := "application/grpc" + strings.TrimPrefix(contentType, "application/grpc-web")
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, r.Body)
+		grpcContentType = "application/grpc" + strings.TrimPrefix(contentType, "application/grpc-web-text")
+	}
+
+	writer := &grpcWebResponseWriter{
+		w:           w,
+		header:      make(http.Header),
+		contentType: contentType,
+		text:        text,
+	}
+	grpcServer.ServeHTTP(writer, grpcRequest(r, grpcContentType, body))
+	writer.finish()
+}
+
+// grpcWebResponseWriter translates gRPC responses into gRPC-Web responses
+type grpcWebResponseWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	contentType string
+	text        bool
+	wroteHeader bool
+
+	// pending holds unencoded bytes for application/grpc-web-text
+	pending bytes.Buffer
+}
+
+func (w *grpcWebResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *grpcWebResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.w.Header()
+	for key, values := range w.header {
+		// trailers are sent in the response body
+		if key == "Trailer" || grpcTrailers[key] || strings.HasPrefix(key, http2.TrailerPrefix) {
+			continue
+		}
+		header[key] = values
+	}
+	header.Set("Content-Type", w.contentType)
+	header.Del("Content-Length")
+	w.w.WriteHeader(code)
+}
+
+func (w *grpcWebResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	if w.text {
+		return w.pending.Write(b)
+	}
+	return w.w.Write(b)
+}
+
+func (w *grpcWebResponseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+	if w.text && w.pending.Len() > 0 {
+		w.w.Write([]byte(base64.StdEncoding.EncodeToString(w.pending.Bytes())))
+		w.pending.Reset()
+	}
+	if flusher, ok := w.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish writes the trailers as the last gRPC-Web frame
+func (w *grpcWebResponseWriter) finish() {
+	var trailers bytes.Buffer
+	for key, values := range w.header {
+		name := strings.TrimPrefix(key, http2.TrailerPrefix)
+		if name == key && !grpcTrailers[key] {
+			continue
+		}
+		for _, value := range values {
+			fmt.Fprintf(&trailers, "%s: %s\r\n", strings.ToLower(name), value)
+		}
+	}
+
+	frame := make([]byte, 5, 5+trailers.Len())
+	frame[0] = 1 << 7
+	binary.BigEndian.PutUint32(frame[1:], uint32(trailers.Len()))
+	w.Write(append(frame, trailers.Bytes()...))
+	w.Flush()
+}
+
+func serveConnect(grpcServer *grpc.Server, w http.ResponseWriter, r *http.Request) {
+	contentType := connectMediaType(r.Header.Get("Content-Type"))
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxConnectBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeConnectError(w, codes.ResourceExhausted, "request body too large")
+			return
+		}
+		writeConnectError(w, codes.InvalidArgument, "error reading request body")
+		return
+	}
+
+	frame := make([]byte, 5, 5+len(body))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(body)))
+	frame = append(frame, body...)
+
+	grpcContentType := "application/grpc+proto"
+	if contentType == "application/json" {
+		grpcContentType = "application/grpc+json"
+	}
+
+	req := grpcRequest(r, grpcContentType, bytes.NewReader(frame))
+	if timeout := r.Header.Get("Connect-Timeout-Ms"); timeout != "" {
+		grpcTimeout, ok := connectTimeout(timeout)
+		if !ok {
+			writeConnectError(w, codes.InvalidArgument, "invalid Connect-Timeout-Ms")
+			return
+		}
+		req.Header.Set("Grpc-Timeout", grpcTimeout)
+	}
+
+	recorder := &grpcRecorder{
+		header: make(http.Header),
+	}
+	grpcServer.ServeHTTP(recorder, req)
+
+	// response metadata as headers, trailers with a `Trailer-` prefix
+	header := w.Header()
+	for key, values := range recorder.header {
+		switch {
+		case key == "Trailer" || key == "Content-Type" || grpcTrailers[key]:
+			continue
+		case strings.HasPrefix(key, http2.TrailerPrefix):
+			header["Trailer-"+strings.TrimPrefix(key, http2.TrailerPrefix)] = values
+		default:
+			header[key] = values
+		}
+	}
+
+	status, _ := strconv.Atoi(recorder.header.Get("Grpc-Status"))
+	if code := codes.Code(status); code != codes.OK {
+		writeConnectError(w, code, recorder.header.Get("Grpc-Message"))
+		return
+	}
+
+	message := recorder.body.Bytes()
+	if len(message) < 5 {
+		writeConnectError(w, codes.Internal, "missing response message")
+		return
+	}
+	header.Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(message[5:])
+}
+
+// connectTimeout converts Connect-Timeout-Ms (up to 10 digits) to Grpc-Timeout (up to 8 digits)
+//
+// Timeouts above the millisecond range are sent in seconds, rounded up,
+// and clamped to the largest gRPC timeout.
+func connectTimeout(value string) (string, bool) {
+	if len(value) > 10 {
+		return "", false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	ms, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return "", false
+	}
+	if ms <= maxGRPCTimeoutValue {
+		return strconv.FormatUint(ms, 10) + "m", true
+	}
+	seconds := (ms + 999) / 1000
+	if seconds > maxGRPCTimeoutValue {
+		seconds = maxGRPCTimeoutValue
+	}
+	return strconv.FormatUint(seconds, 10) + "S", true
+}
+
+func writeConnectError(w http.ResponseWriter, code codes.Code, message string) {
+	connect, ok := grpcToConnect[code]
+	if !ok {
+		connect = grpcToConnect[codes.Unknown]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(connect.status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"code":    connect.code,
+		"message": message,
+	})
+}
+
+// grpcRecorder buffers a gRPC response
+type grpcRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *grpcRecorder) Header() http.Header         { return r.header }
+func (r *grpcRecorder) WriteHeader(int)             {}
+func (r *grpcRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *grpcRecorder) Flush()                      {}
+
+// jsonCodec is a gRPC codec for protobuf JSON encoding
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("failed to marshal, message is %T, want proto.Message", v)
+	}
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{}).Marshal(&buf, message); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal, message is %T, want proto.Message", v)
+	}
+	return (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(bytes.NewReader(data), message)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
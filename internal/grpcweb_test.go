@@ -0,0 +1,180 @@
+package internal_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/titpetric/microservice/internal"
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+func newGRPCWebTestServer(t *testing.T) (*httptest.Server, *testService) {
+	svc := new(testService)
+	service := stats.NewStatsServiceValidator(svc)
+
+	grpcServer := internal.NewGRPCServer()
+	stats.RegisterStatsServiceServer(grpcServer, service)
+	twirpHandler := stats.NewStatsServiceServer(service, internal.NewServerHooks())
+
+	return httptest.NewServer(internal.WrapAll(internal.WrapGRPCWeb(grpcServer, twirpHandler), nil)), svc
+}
+
+func TestWrapGRPCWeb(t *testing.T) {
+	server, svc := newGRPCWebTestServer(t)
+	defer server.Close()
+
+	request := func(contentType string, message *stats.PushRequest) (string, []byte) {
+		body, err := proto.Marshal(message)
+		if err != nil {
+			t.Fatalf("Unexpected error on proto.Marshal: %+v", err)
+		}
+		frame := append([]byte{0, 0, 0, 0, byte(len(body))}, body...)
+		if strings.HasPrefix(contentType, "application/grpc-web-text") {
+			frame = []byte(base64.StdEncoding.EncodeToString(frame))
+		}
+
+		resp, err := http.Post(server.URL+"/stats.StatsService/Push", contentType, bytes.NewReader(frame))
+		if err != nil {
+			t.Fatalf("Unexpected error on POST: %+v", err)
+		}
+		defer resp.Body.Close()
+
+		result, _ := ioutil.ReadAll(resp.Body)
+		if strings.HasPrefix(contentType, "application/grpc-web-text") {
+			// each flush is a separately padded chunk, decode in 4 byte groups
+			encoded, decoded := result, []byte{}
+			for len(encoded) >= 4 {
+				chunk, err := base64.StdEncoding.DecodeString(string(encoded[:4]))
+				if err != nil {
+					t.Fatalf("Unexpected error decoding response: %+v", err)
+				}
+				decoded, encoded = append(decoded, chunk...), encoded[4:]
+			}
+			result = decoded
+		}
+		return resp.Header.Get("Content-Type"), result
+	}
+
+	for _, contentType := range []string{"application/grpc-web+proto", "application/grpc-web-text"} {
+		svc.ip = ""
+		responseType, body := request(contentType, &stats.PushRequest{Property: "news", Section: 1, Id: 1})
+		if responseType != contentType {
+			t.Errorf("Unexpected content type %q, expected %q", responseType, contentType)
+		}
+		if !bytes.Contains(body, []byte("grpc-status: 0\r\n")) {
+			t.Errorf("Expected grpc-status 0 in trailers, got %q", body)
+		}
+		if svc.ip != "127.0.0.1" {
+			t.Errorf("Unexpected IP from %s request: %q", contentType, svc.ip)
+		}
+
+		_, body = request(contentType, &stats.PushRequest{Property: "news"})
+		if !bytes.Contains(body, []byte("grpc-status: 3\r\n")) {
+			t.Errorf("Expected grpc-status 3 in trailers, got %q", body)
+		}
+		if body[len(body)-1] != '\n' || body[0]&0x80 == 0 {
+			t.Errorf("Expected a single trailer frame, got %q", body)
+		}
+	}
+}
+
+func TestWrapConnect(t *testing.T) {
+	server, svc := newGRPCWebTestServer(t)
+	defer server.Close()
+
+	postAs := func(contentType, body string) *http.Response {
+		resp, err := http.Post(server.URL+"/stats.StatsService/Push", contentType, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Unexpected error on POST: %+v", err)
+		}
+		return resp
+	}
+	post := func(body string) *http.Response {
+		return postAs("application/json", body)
+	}
+
+	resp := post(`{"property":"news","section":1,"id":1}`)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "{}" {
+		t.Errorf("Unexpected response %d: %q", resp.StatusCode, body)
+	}
+	if svc.ip != "127.0.0.1" {
+		t.Errorf("Unexpected IP from connect request: %q", svc.ip)
+	}
+
+	// media type parameters are allowed
+	resp = postAs("application/json; charset=utf-8", `{"property":"news","section":1,"id":2}`)
+	body, _ = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "{}" || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected response %d (%s): %q", resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	}
+
+	resp = post(`{"property":"news"}`)
+	defer resp.Body.Close()
+	var connectErr struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&connectErr); err != nil {
+		t.Fatalf("Unexpected error decoding connect error: %+v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || connectErr.Code != "invalid_argument" {
+		t.Errorf("Unexpected connect error %d: %+v", resp.StatusCode, connectErr)
+	}
+}
+
+func TestWrapConnectLimits(t *testing.T) {
+	server, _ := newGRPCWebTestServer(t)
+	defer server.Close()
+
+	post := func(body string, timeout string) (int, string) {
+		req, _ := http.NewRequest("POST", server.URL+"/stats.StatsService/Push", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if timeout != "" {
+			req.Header.Set("Connect-Timeout-Ms", timeout)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error on POST: %+v", err)
+		}
+		defer resp.Body.Close()
+		var connectErr struct {
+			Code string `json:"code"`
+		}
+		json.NewDecoder(resp.Body).Decode(&connectErr)
+		return resp.StatusCode, connectErr.Code
+	}
+
+	valid := `{"property":"news","section":1,"id":1}`
+	tests := []struct {
+		name    string
+		body    string
+		timeout string
+		status  int
+		code    string
+	}{
+		{"timeout", valid, "5000", http.StatusOK, ""},
+		{"max timeout", valid, "9999999999", http.StatusOK, ""},
+		{"negative timeout", valid, "-1", http.StatusBadRequest, "invalid_argument"},
+		{"timeout with unit", valid, "5S", http.StatusBadRequest, "invalid_argument"},
+		{"timeout too long", valid, "10000000000", http.StatusBadRequest, "invalid_argument"},
+		{"body too large", `{"property":"` + strings.Repeat("a", 5<<20) + `"}`, "", http.StatusTooManyRequests, "resource_exhausted"},
+	}
+	for _, test := range tests {
+		status, code := post(test.body, test.timeout)
+		if status != test.status || code != test.code {
+			t.Errorf("%s: expected %d %q, got %d %q", test.name, test.status, test.code, status, code)
+		}
+	}
+}
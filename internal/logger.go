@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"log/slog"
+)
+
+// LogOptions configure the structured logger
+type LogOptions struct {
+	// Level is the minimum level (`debug`, `info` (default), `warn`, `error`)
+	Level string
+	// Format is `json` (default) or `logfmt`
+	Format string
+}
+
+// NewLogger produces a structured logger for injection
+//
+// The logger is also set as the slog and log package default, and adds
+// the request ID from the context to every log line.
+func NewLogger(options LogOptions) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(options.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+	handlerOptions := &slog.HandlerOptions{
+		Level: level,
+	}
+
+	var handler slog.Handler
+	switch strings.ToLower(options.Format) {
+	case "logfmt", "text":
+		handler = slog.NewTextHandler(os.Stderr, handlerOptions)
+	default:
+		handler = slog.NewJSONHandler(os.Stderr, handlerOptions)
+	}
+
+	logger := slog.New(&requestIDHandler{handler})
+	slog.SetDefault(logger)
+	return logger
+}
+
+// requestIDHandler adds the request ID from the context to log records
+type requestIDHandler struct {
+	slog.Handler
+}
+
+func (h *requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := GetRequestIDFromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &requestIDHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *requestIDHandler) WithGroup(name string) slog.Handler {
+	return &requestIDHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+
+	"database/sql"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Tracer is a tracing backend for HTTP, RPC and SQL
+type Tracer interface {
+	// WrapHandler traces incoming HTTP requests, continuing propagated traces
+	WrapHandler(h http.Handler) http.Handler
+	// WrapClient traces outgoing HTTP requests, propagating the trace
+	WrapClient(client *http.Client) *http.Client
+	// OpenDB opens a traced database handle
+	OpenDB(driver, dsn string) (*sql.DB, error)
+
+	// StartSpan starts a span, continuing the trace in ctx
+	StartSpan(ctx context.Context, name, spanType string) (context.Context, Span)
+	// Detach returns a background context, continuing the trace in ctx
+	Detach(ctx context.Context) context.Context
+	// CaptureError records err with the trace in ctx
+	CaptureError(ctx context.Context, err error)
+
+	// Close flushes buffered spans, giving up when ctx is done
+	Close(ctx context.Context) error
+}
+
+// Span is a traced operation
+type Span interface {
+	End()
+}
+
+// TracingOptions configure the tracing backend
+type TracingOptions struct {
+	// Tracer is `elastic` (default), `otel` or `none`
+	Tracer string
+	// Exporter is the OpenTelemetry exporter, see NewOpenTelemetryTracer
+	Exporter string
+}
+
+// tracerValue wraps a Tracer, atomic.Value requires a consistent type
+type tracerValue struct {
+	Tracer
+}
+
+var tracer atomic.Value
+
+// NewTracer creates the Tracer selected with options
+func NewTracer(options TracingOptions) (Tracer, error) {
+	switch options.Tracer {
+	case "otel":
+		return NewOpenTelemetryTracer(options.Exporter)
+	case "none":
+		return NoopTracer{}, nil
+	case "", "elastic":
+		return ElasticTracer{}, nil
+	}
+	return nil, errors.Errorf("unknown tracer: '%s'", options.Tracer)
+}
+
+// SetTracer sets the Tracer returned by GetTracer
+//
+// The tracer should be set on startup, before creating clients,
+// database connections and HTTP handlers.
+func SetTracer(t Tracer) {
+	tracer.Store(tracerValue{t})
+}
+
+// GetTracer returns the Tracer set with SetTracer, ElasticTracer by default
+func GetTracer() Tracer {
+	if t, ok := tracer.Load().(tracerValue); ok {
+		return t.Tracer
+	}
+	return ElasticTracer{}
+}
+
+// NoopTracer disables tracing
+type NoopTracer struct{}
+
+// WrapHandler returns h
+func (NoopTracer) WrapHandler(h http.Handler) http.Handler { return h }
+
+// WrapClient returns client
+func (NoopTracer) WrapClient(client *http.Client) *http.Client { return client }
+
+// OpenDB opens a database handle
+func (NoopTracer) OpenDB(driver, dsn string) (*sql.DB, error) { return sql.Open(driver, dsn) }
+
+// StartSpan returns ctx and a no-op span
+func (NoopTracer) StartSpan(ctx context.Context, _, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// Detach returns a background context
+func (NoopTracer) Detach(context.Context) context.Context { return context.Background() }
+
+// CaptureError does nothing
+func (NoopTracer) CaptureError(context.Context, error) {}
+
+// Close does nothing
+func (NoopTracer) Close(context.Context) error { return nil }
+
+type noopSpan struct{}
+
+func (noopSpan) End() {}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"context"
+
+	"database/sql"
+	"net/http"
+
+	"go.elastic.co/apm"
+	"go.elastic.co/apm/module/apmhttp"
+	"go.elastic.co/apm/module/apmsql"
+)
+
+// ElasticTracer traces with Elastic APM, configured with ELASTIC_APM_* env
+type ElasticTracer struct{}
+
+type elasticTraceKey struct{}
+
+// WrapHandler traces incoming requests as transactions
+func (ElasticTracer) WrapHandler(h http.Handler) http.Handler {
+	return apmhttp.Wrap(h)
+}
+
+// WrapClient traces outgoing requests as spans
+func (ElasticTracer) WrapClient(client *http.Client) *http.Client {
+	return apmhttp.WrapClient(client)
+}
+
+// OpenDB opens a database handle with apmsql, drivers are registered in db
+func (ElasticTracer) OpenDB(driver, dsn string) (*sql.DB, error) {
+	return apmsql.Open(driver, dsn)
+}
+
+// StartSpan starts a span, or a transaction if ctx has none
+func (ElasticTracer) StartSpan(ctx context.Context, name, spanType string) (context.Context, Span) {
+	if apm.TransactionFromContext(ctx) != nil {
+		span, ctx := apm.StartSpan(ctx, name, spanType)
+		return ctx, span
+	}
+
+	opts := apm.TransactionOptions{}
+	if trace, ok := ctx.Value(elasticTraceKey{}).(apm.TraceContext); ok {
+		opts.TraceContext = trace
+	}
+	tx := apm.DefaultTracer.StartTransactionOptions(name, spanType, opts)
+	return apm.ContextWithTransaction(ctx, tx), tx
+}
+
+// Detach keeps the trace context, new spans start a transaction in the same trace
+func (ElasticTracer) Detach(ctx context.Context) context.Context {
+	var trace apm.TraceContext
+	if tx := apm.TransactionFromContext(ctx); tx != nil {
+		trace = tx.TraceContext()
+	}
+	if span := apm.SpanFromContext(ctx); span != nil {
+		trace = span.TraceContext()
+	}
+	return context.WithValue(context.Background(), elasticTraceKey{}, trace)
+}
+
+// CaptureError sends err to APM
+func (ElasticTracer) CaptureError(ctx context.Context, err error) {
+	apm.CaptureError(ctx, err).Send()
+}
+
+// Close flushes buffered transactions, aborting the flush when ctx is done
+func (ElasticTracer) Close(ctx context.Context) error {
+	apm.DefaultTracer.Flush(ctx.Done())
+	return ctx.Err()
+}
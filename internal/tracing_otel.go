@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"context"
+
+	"database/sql"
+	"net/http"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenTelemetryTracer traces with OpenTelemetry
+type OpenTelemetryTracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// NewOpenTelemetryTracer creates an *OpenTelemetryTracer
+//
+// The exporter `otlp` (default) sends spans over OTLP/HTTP, configured
+// with OTEL_EXPORTER_OTLP_* env, and `console` writes them to stdout.
+// The tracer is registered as the global OpenTelemetry provider with
+// W3C trace context propagation.
+func NewOpenTelemetryTracer(exporterName string) (*OpenTelemetryTracer, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch exporterName {
+	case "console", "stdout":
+		exporter, err = stdouttrace.New()
+	default:
+		exporter, err = otlptracehttp.New(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenTelemetryTracerWithExporter(exporter), nil
+}
+
+// NewOpenTelemetryTracerWithExporter creates an *OpenTelemetryTracer sending spans to exporter
+func NewOpenTelemetryTracerWithExporter(exporter sdktrace.SpanExporter) *OpenTelemetryTracer {
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return &OpenTelemetryTracer{
+		provider: provider,
+		tracer:   provider.Tracer("github.com/titpetric/microservice"),
+	}
+}
+
+// WrapHandler traces incoming requests
+func (t *OpenTelemetryTracer) WrapHandler(h http.Handler) http.Handler {
+	return otelhttp.NewHandler(h, "http", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method + " " + r.URL.Path
+	}))
+}
+
+// WrapClient traces outgoing requests
+func (t *OpenTelemetryTracer) WrapClient(client *http.Client) *http.Client {
+	result := *client
+	result.Transport = otelhttp.NewTransport(client.Transport)
+	return &result
+}
+
+// OpenDB opens a traced database handle
+func (t *OpenTelemetryTracer) OpenDB(driver, dsn string) (*sql.DB, error) {
+	return otelsql.Open(driver, dsn)
+}
+
+// StartSpan starts a span
+func (t *OpenTelemetryTracer) StartSpan(ctx context.Context, name, spanType string) (context.Context, Span) {
+	ctx, span := t.tracer.Start(ctx, name)
+	return ctx, otelSpan{span}
+}
+
+// Detach keeps the span context as the remote parent of new spans
+func (t *OpenTelemetryTracer) Detach(ctx context.Context) context.Context {
+	return trace.ContextWithRemoteSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+// CaptureError records err on the current span
+func (t *OpenTelemetryTracer) CaptureError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Close flushes and stops the exporter
+func (t *OpenTelemetryTracer) Close(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
+
+type otelSpan struct {
+	trace.Span
+}
+
+func (s otelSpan) End() {
+	s.Span.End()
+}
@@ -0,0 +1,70 @@
+package internal_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/titpetric/microservice/internal"
+)
+
+func TestOpenTelemetryTracer(t *testing.T) {
+	tracer := internal.NewOpenTelemetryTracerWithExporter(tracetest.NewInMemoryExporter())
+	defer tracer.Close(context.Background())
+
+	var traceID trace.TraceID
+	server := httptest.NewServer(tracer.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID = trace.SpanContextFromContext(r.Context()).TraceID()
+	})))
+	defer server.Close()
+
+	ctx, span := tracer.StartSpan(context.Background(), "test", "request")
+	defer span.End()
+
+	req, _ := http.NewRequest("POST", server.URL, nil)
+	resp, err := tracer.WrapClient(http.DefaultClient).Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	resp.Body.Close()
+
+	want := trace.SpanContextFromContext(ctx).TraceID()
+	if !want.IsValid() || traceID != want {
+		t.Errorf("Expected trace %s, got %s", want, traceID)
+	}
+
+	// background work continues the trace
+	detached, _ := tracer.StartSpan(tracer.Detach(ctx), "async", "async")
+	if got := trace.SpanContextFromContext(detached).TraceID(); got != want {
+		t.Errorf("Expected detached trace %s, got %s", want, got)
+	}
+}
+
+func TestElasticTracerClose(t *testing.T) {
+	// the APM server isn't reachable, Close gives up when ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	tracer := internal.ElasticTracer{}
+	_, span := tracer.StartSpan(context.Background(), "test", "request")
+	span.End()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tracer.Close(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err != nil && err != context.DeadlineExceeded {
+			t.Errorf("Unexpected error on Close: %+v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected Close to return after ctx is done")
+	}
+}
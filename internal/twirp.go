@@ -2,16 +2,32 @@ package internal
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/twitchtv/twirp"
-	"go.elastic.co/apm"
 )
 
-// NewServerHooks provides an error logging hook with Elastic APM
+type twirpSpanKey struct{}
+
+// NewServerHooks provides tracing and error logging hooks
 func NewServerHooks() *twirp.ServerHooks {
 	return &twirp.ServerHooks{
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			service, _ := twirp.ServiceName(ctx)
+			method, _ := twirp.MethodName(ctx)
+			ctx, span := GetTracer().StartSpan(ctx, service+"/"+method, "twirp")
+			return context.WithValue(ctx, twirpSpanKey{}, span), nil
+		},
+		ResponseSent: func(ctx context.Context) {
+			if span, ok := ctx.Value(twirpSpanKey{}).(Span); ok {
+				span.End()
+			}
+		},
 		Error: func(ctx context.Context, err twirp.Error) context.Context {
-			apm.CaptureError(ctx, err).Send()
+			GetTracer().CaptureError(ctx, err)
+			method, _ := twirp.MethodName(ctx)
+			// Error() includes the cause of database errors, clients only get Msg()
+			slog.WarnContext(ctx, "RPC error", "method", method, "code", err.Code(), "err", err.Error())
 			return ctx
 		},
 	}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"github.com/twitchtv/twirp"
+)
+
+// Validate returns the first failed validation rule
+//
+// Rules are produced with Required, OneOf and Invalid, for example:
+//
+//	internal.Validate(
+//		internal.Required("property", r.Property != ""),
+//		internal.OneOf("property", r.Property, "news"),
+//	)
+func Validate(rules ...error) error {
+	for _, err := range rules {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Required produces an invalid argument error for field if !ok
+func Required(field string, ok bool) error {
+	if ok {
+		return nil
+	}
+	return twirp.RequiredArgumentError(field)
+}
+
+// OneOf produces an invalid argument error if value isn't in allowed
+func OneOf(field string, value string, allowed ...string) error {
+	for _, v := range allowed {
+		if v == value {
+			return nil
+		}
+	}
+	return twirp.InvalidArgumentError(field, "is invalid").WithMeta("value", value)
+}
+
+// Invalid produces an invalid argument error for field if !ok
+func Invalid(field string, ok bool, message string) error {
+	if ok {
+		return nil
+	}
+	return twirp.InvalidArgumentError(field, message)
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/twitchtv/twirp"
+)
+
+func TestValidate(t *testing.T) {
+	err := Validate(
+		Required("property", true),
+		OneOf("property", "blog", "news"),
+		Required("id", false),
+	)
+	twerr, ok := err.(twirp.Error)
+	if !ok {
+		t.Fatalf("Expected twirp.Error, got %+v", err)
+	}
+	if twerr.Code() != twirp.InvalidArgument {
+		t.Errorf("Unexpected error code: %s", twerr.Code())
+	}
+	if twerr.Meta("argument") != "property" {
+		t.Errorf("Unexpected argument meta: %s", twerr.Meta("argument"))
+	}
+	if err := Validate(Required("id", true), OneOf("property", "news", "news")); err != nil {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+}
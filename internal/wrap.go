@@ -1,17 +1,20 @@
 package internal
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"strings"
 
 	"net/http"
-
-	"go.elastic.co/apm/module/apmhttp"
 )
 
 // WrapAll wraps a http.Handler with all needed handlers for our service
-func WrapAll(h http.Handler) http.Handler {
+func WrapAll(h http.Handler, origins CORSOrigins) http.Handler {
 	h = WrapWithIP(h)
-	h = apmhttp.Wrap(h)
+	h = WrapWithRequestID(h)
+	h = WrapWithCredentials(h)
+	h = WrapWithCORS(h, origins)
+	h = GetTracer().WrapHandler(h)
 	return h
 }
 
@@ -38,3 +41,40 @@ func WrapWithIP(h http.Handler) http.Handler {
 		h.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// WrapWithRequestID wraps a http.Handler to assign or propagate X-Request-ID
+//
+// The request ID is stored in the context, and returned in the response headers.
+func WrapWithRequestID(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+
+		ctx := r.Context()
+		ctx = SetRequestIDToContext(ctx, id)
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID accepts short printable IDs, to keep log lines sane
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
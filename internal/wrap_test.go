@@ -0,0 +1,37 @@
+package internal_test
+
+import (
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/titpetric/microservice/internal"
+)
+
+func TestWrapWithRequestID(t *testing.T) {
+	var requestID string
+	handler := internal.WrapWithRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = internal.GetRequestIDFromContext(r.Context())
+	}))
+
+	// propagated
+	req := httptest.NewRequest("POST", "/twirp/stats.StatsService/Push", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if requestID != "abc-123" || w.Header().Get("X-Request-ID") != "abc-123" {
+		t.Errorf("Expected propagated request ID, got %q (response %q)", requestID, w.Header().Get("X-Request-ID"))
+	}
+
+	// generated, invalid IDs are replaced
+	for _, id := range []string{"", "with space"} {
+		req = httptest.NewRequest("POST", "/twirp/stats.StatsService/Push", nil)
+		req.Header.Set("X-Request-ID", id)
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if len(requestID) != 32 || requestID == id || w.Header().Get("X-Request-ID") != requestID {
+			t.Errorf("Expected generated request ID, got %q (response %q)", requestID, w.Header().Get("X-Request-ID"))
+		}
+	}
+}
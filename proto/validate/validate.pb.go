@@ -0,0 +1,95 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/validate/validate.proto
+
+package validate
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+var E_Required = &proto.ExtensionDesc{
+	ExtendedType:  (*descriptor.FieldOptions)(nil),
+	ExtensionType: (*bool)(nil),
+	Field:         51001,
+	Name:          "validate.required",
+	Tag:           "varint,51001,opt,name=required",
+	Filename:      "proto/validate/validate.proto",
+}
+
+var E_Min = &proto.ExtensionDesc{
+	ExtendedType:  (*descriptor.FieldOptions)(nil),
+	ExtensionType: (*float64)(nil),
+	Field:         51002,
+	Name:          "validate.min",
+	Tag:           "fixed64,51002,opt,name=min",
+	Filename:      "proto/validate/validate.proto",
+}
+
+var E_Max = &proto.ExtensionDesc{
+	ExtendedType:  (*descriptor.FieldOptions)(nil),
+	ExtensionType: (*float64)(nil),
+	Field:         51003,
+	Name:          "validate.max",
+	Tag:           "fixed64,51003,opt,name=max",
+	Filename:      "proto/validate/validate.proto",
+}
+
+var E_Pattern = &proto.ExtensionDesc{
+	ExtendedType:  (*descriptor.FieldOptions)(nil),
+	ExtensionType: (*string)(nil),
+	Field:         51004,
+	Name:          "validate.pattern",
+	Tag:           "bytes,51004,opt,name=pattern",
+	Filename:      "proto/validate/validate.proto",
+}
+
+var E_In = &proto.ExtensionDesc{
+	ExtendedType:  (*descriptor.FieldOptions)(nil),
+	ExtensionType: ([]string)(nil),
+	Field:         51005,
+	Name:          "validate.in",
+	Tag:           "bytes,51005,rep,name=in",
+	Filename:      "proto/validate/validate.proto",
+}
+
+func init() {
+	proto.RegisterExtension(E_Required)
+	proto.RegisterExtension(E_Min)
+	proto.RegisterExtension(E_Max)
+	proto.RegisterExtension(E_Pattern)
+	proto.RegisterExtension(E_In)
+}
+
+func init() { proto.RegisterFile("proto/validate/validate.proto", fileDescriptor_2d27227cabb8dfe7) }
+
+var fileDescriptor_2d27227cabb8dfe7 = []byte{
+	// 217 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0xbf, 0x4e, 0xc3, 0x30,
+	0x10, 0xc6, 0xd5, 0x66, 0x20, 0xf5, 0xd8, 0x09, 0x21, 0x55, 0xca, 0xd8, 0xc9, 0x06, 0x36, 0xc2,
+	0xc6, 0xc0, 0x8a, 0x94, 0x91, 0xcd, 0xb5, 0x8f, 0x70, 0x52, 0x92, 0x33, 0x97, 0x4b, 0xd5, 0xa7,
+	0xe8, 0xfb, 0xf0, 0xef, 0xdd, 0x10, 0x4e, 0xe3, 0xd5, 0xdb, 0xdd, 0xa7, 0xdf, 0x4f, 0x77, 0xfa,
+	0xd4, 0x2e, 0x30, 0x09, 0x99, 0xa3, 0xed, 0xd0, 0x5b, 0x81, 0x34, 0xe8, 0x98, 0x6f, 0xcb, 0x65,
+	0xbf, 0xa9, 0x5a, 0xa2, 0xb6, 0x03, 0x13, 0xf3, 0xc3, 0xf4, 0x66, 0x3c, 0x8c, 0x8e, 0x31, 0x08,
+	0xf1, 0xcc, 0xd6, 0x8f, 0xaa, 0x64, 0xf8, 0x98, 0x90, 0xc1, 0x6f, 0x77, 0x7a, 0xc6, 0xf5, 0x82,
+	0xeb, 0x67, 0x84, 0xce, 0xbf, 0x04, 0x41, 0x1a, 0xc6, 0xeb, 0xcf, 0x73, 0x51, 0xad, 0xf6, 0x65,
+	0x93, 0x84, 0xfa, 0x4e, 0x15, 0x3d, 0x0e, 0x39, 0xef, 0x2b, 0x7a, 0xab, 0xe6, 0x9f, 0x8d, 0x8a,
+	0x3d, 0xe5, 0x94, 0xef, 0xa4, 0xd8, 0x53, 0xfd, 0xa0, 0xae, 0x82, 0x15, 0x01, 0xce, 0x5e, 0xfa,
+	0x89, 0xda, 0xa6, 0x59, 0xf8, 0xda, 0xa8, 0x75, 0xfe, 0xbf, 0xdf, 0x73, 0x51, 0x15, 0xfb, 0x4d,
+	0xb3, 0xc6, 0xe1, 0xe9, 0xfe, 0xf5, 0xb6, 0x45, 0x79, 0x9f, 0x0e, 0xda, 0x51, 0x6f, 0x04, 0x25,
+	0x80, 0x30, 0x3a, 0xd3, 0xa3, 0x63, 0x1a, 0x81, 0x8f, 0xe8, 0x2e, 0x6d, 0xa6, 0xd2, 0xff, 0x06,
+	0x00, 0xaf, 0x0c, 0x81, 0xd6, 0x8e, 0x01, 0x00, 0x00,
+}
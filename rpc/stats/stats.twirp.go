@@ -857,18 +857,20 @@ func callClientError(ctx context.Context, h *twirp.ClientHooks, err twirp.Error)
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 199 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x8f, 0xcd, 0x6a, 0x85, 0x30,
-	0x10, 0x85, 0xd1, 0xfe, 0x4f, 0xad, 0x8b, 0x94, 0x42, 0x70, 0x25, 0xae, 0x84, 0x52, 0x03, 0xed,
-	0x03, 0x14, 0xfa, 0x04, 0x45, 0x77, 0xdd, 0xd5, 0x38, 0xd4, 0x59, 0x68, 0xd2, 0xcc, 0x58, 0xe8,
-	0xdb, 0x5f, 0x34, 0x5c, 0xb9, 0x77, 0x13, 0xf8, 0x4e, 0xe0, 0x9b, 0x73, 0xe0, 0x29, 0x78, 0x6b,
-	0x58, 0xbe, 0x85, 0xe3, 0xdb, 0xf8, 0xe0, 0xc4, 0xa9, 0xab, 0x0d, 0xaa, 0x0e, 0xee, 0x3f, 0x17,
-	0x1e, 0x5b, 0xfc, 0x5d, 0x90, 0x45, 0x15, 0x70, 0xeb, 0x83, 0xf3, 0x18, 0xe4, 0x5f, 0x27, 0x65,
-	0x52, 0xdf, 0xb5, 0x3b, 0x2b, 0x0d, 0x37, 0x8c, 0x56, 0xc8, 0xcd, 0x3a, 0x2d, 0x93, 0xfa, 0xa1,
-	0x3d, 0xa2, 0xca, 0x21, 0xa5, 0x41, 0x5f, 0x6c, 0x61, 0x4a, 0x43, 0x95, 0x43, 0x16, 0xa5, 0xec,
-	0xdd, 0xcc, 0xf8, 0xfa, 0x0e, 0x59, 0xb7, 0x5e, 0xeb, 0x30, 0xfc, 0x91, 0x45, 0x65, 0xe0, 0x72,
-	0xfd, 0x57, 0xaa, 0x89, 0x8d, 0x4e, 0x1a, 0x14, 0x8f, 0x67, 0x59, 0x14, 0x7c, 0xbc, 0x7c, 0x3d,
-	0xff, 0x90, 0x8c, 0x4b, 0xdf, 0x58, 0x37, 0x19, 0x21, 0xf1, 0x28, 0x81, 0xac, 0x99, 0xc8, 0x06,
-	0xc7, 0xd1, 0x6a, 0xf6, 0x9d, 0xfd, 0xf5, 0x36, 0xf1, 0xed, 0x10, 0x00, 0x00, 0xff, 0xff, 0x82,
-	0xec, 0xac, 0xfd, 0xfb, 0x00, 0x00, 0x00,
+	// 231 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x50, 0xcb, 0x4a, 0xc4, 0x30,
+	0x14, 0x25, 0xb5, 0xbe, 0x62, 0x75, 0x11, 0x15, 0xc2, 0x80, 0x32, 0xcc, 0xaa, 0x20, 0x36, 0xa0,
+	0x1f, 0x20, 0xf4, 0x0b, 0xa4, 0xb3, 0x73, 0xd7, 0x49, 0x2f, 0xce, 0x05, 0xdb, 0xc4, 0xdc, 0xdb,
+	0x11, 0xff, 0xd0, 0x6f, 0x71, 0x3b, 0x3f, 0x20, 0x6d, 0xb0, 0x74, 0x36, 0x21, 0xe7, 0xc1, 0xc9,
+	0x39, 0x91, 0xb7, 0xc1, 0x5b, 0x43, 0x5c, 0x33, 0xc5, 0xb3, 0xf0, 0xc1, 0xb1, 0x53, 0xc7, 0x23,
+	0x58, 0xdc, 0x8d, 0xc8, 0xec, 0xea, 0x0f, 0x6c, 0x6a, 0x86, 0xe9, 0x12, 0x5d, 0xab, 0x56, 0x5e,
+	0xbc, 0xf6, 0xb4, 0xad, 0xe0, 0xb3, 0x07, 0x62, 0x95, 0xcb, 0x33, 0x1f, 0x9c, 0x87, 0xc0, 0xdf,
+	0x5a, 0x2c, 0x45, 0x7e, 0x5e, 0x66, 0x3f, 0x7b, 0x2d, 0x7e, 0xf7, 0x3a, 0xed, 0xe0, 0x8b, 0xaa,
+	0x49, 0x55, 0xf7, 0xf2, 0x94, 0xc0, 0x32, 0xba, 0x4e, 0x27, 0x4b, 0x91, 0x5f, 0x96, 0xe9, 0x60,
+	0xac, 0xfe, 0x49, 0x75, 0x23, 0x13, 0x6c, 0xf4, 0xd1, 0x4c, 0x4a, 0xb0, 0x59, 0x5d, 0xc9, 0x2c,
+	0x3e, 0x47, 0xde, 0x75, 0x04, 0x4f, 0x2f, 0x32, 0x5b, 0x0f, 0x35, 0xd7, 0x10, 0x76, 0x68, 0x41,
+	0x19, 0x99, 0x0e, 0xba, 0x52, 0x45, 0x9c, 0x32, 0xeb, 0xb6, 0xb8, 0x3e, 0xe0, 0x62, 0x40, 0xf9,
+	0xf8, 0xf6, 0xf0, 0x8e, 0xbc, 0xed, 0x37, 0x85, 0x75, 0xad, 0x61, 0x64, 0x0f, 0x1c, 0xd0, 0x9a,
+	0x16, 0x6d, 0x70, 0x14, 0x53, 0xcd, 0xf4, 0x41, 0x9b, 0x93, 0x71, 0xf5, 0xf3, 0xdf, 0x00, 0x49,
+	0xbf, 0xa3, 0xb2, 0x34, 0x01, 0x00, 0x00,
 }
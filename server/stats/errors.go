@@ -2,8 +2,26 @@ package stats
 
 import (
 	"errors"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/titpetric/microservice/internal"
 )
 
 var (
 	errFlusherDisabled = errors.New("Flusher is disabled, shutting down")
+	errQueueFull       = errors.New("Queue is full, try again later")
 )
+
+// twirpError maps service and database errors to twirp error codes
+func twirpError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case errFlusherDisabled:
+		return twirp.NewError(twirp.Unavailable, err.Error())
+	case errQueueFull:
+		return twirp.NewError(twirp.ResourceExhausted, err.Error())
+	}
+	return internal.DBError(err)
+}
@@ -2,98 +2,201 @@ package stats
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"strings"
+	"log/slog"
+	"sync"
 	"time"
 
-	"github.com/jmoiron/sqlx"
 	"go.uber.org/atomic"
+
+	"github.com/titpetric/microservice/config"
+	"github.com/titpetric/microservice/internal"
+)
+
+const (
+	// flusherQueueCount is the number of queues, a power of two
+	flusherQueueCount = 1 << 4
+	// flusherQueueSize is the capacity of each queue between flushes
+	flusherQueueSize = 1 << 14
 )
 
 // Flusher is a context-driven background data flush job
+//
+// Items are spread over flusherQueueCount bounded queues, each holding
+// up to flusherQueueSize items between flushes. A queue at half capacity
+// triggers a flush before the next tick. If a queue fills up before it's
+// flushed, e.g. when the sink is slow, Push returns errQueueFull, which is
+// returned to clients as twirp.ResourceExhausted, so they can back off.
 type Flusher struct {
 	context.Context
 	finish func()
 
 	enabled *atomic.Bool
+	// pushing is held for reading by Push, and for writing when
+	// disabling, so no item is queued after the final flush
+	pushing sync.RWMutex
 
-	// queueIndex is a key for []queues
-	queueIndex *atomic.Uint32
-	// queueMask is a masking value for queueIndex -> key
+	// queueMask is a masking value for item ID hash -> key
 	queueMask uint32
+	// queueFlushLength triggers a flush before the next tick
+	queueFlushLength int
 	// queues hold a set of writable queues
 	queues []*Queue
 
-	db *sqlx.DB
+	// flushNow is signalled when a queue is filling up
+	flushNow chan struct{}
+	// flushing prevents overlapping flushes
+	flushing internal.Semaphore
+	// workers is the number of queues flushed concurrently
+	workers int
+	// interval is the time between flushes
+	interval time.Duration
+
+	sink Sink
+	log  *slog.Logger
 }
 
-// NewFlusher creates a *Flusher
-func NewFlusher(ctx context.Context, db *sqlx.DB) (*Flusher, error) {
-	queueSize := 1 << 4
+// NewFlusher creates a *Flusher, zero options fall back to defaults
+func NewFlusher(ctx context.Context, sink Sink, log *slog.Logger, options config.Flusher) (*Flusher, error) {
 	job := &Flusher{
-		db:         db,
-		enabled:    atomic.NewBool(true),
-		queueIndex: atomic.NewUint32(0),
-		queueMask:  uint32(queueSize - 1),
-		queues:     NewQueues(queueSize),
+		sink:             sink,
+		log:              log,
+		enabled:          atomic.NewBool(true),
+		queueMask:        uint32(flusherQueueCount - 1),
+		queueFlushLength: flusherQueueSize / 2,
+		queues:           NewQueues(flusherQueueCount, flusherQueueSize),
+		flushNow:         make(chan struct{}, 1),
+		workers:          options.Workers,
+		interval:         options.Interval,
+	}
+	if job.workers < 1 {
+		job.workers = 4
+	}
+	if job.interval <= 0 {
+		job.interval = 5 * time.Second
 	}
 	job.Context, job.finish = context.WithCancel(context.Background())
 	go job.run(ctx)
 	return job, nil
 }
 
-// Push spreads queue writes evenly across all queues
+// Push spreads queue writes across all queues, based on item ID
+//
+// Push takes ownership of item, returning it to the pool on error.
+// It returns errQueueFull if the item's queue is full.
 func (job *Flusher) Push(item *Incoming) error {
-	if job.enabled.Load() {
-		index := job.queueIndex.Inc() & job.queueMask
-		return job.queues[index].Push(item)
+	job.pushing.RLock()
+	defer job.pushing.RUnlock()
+
+	if !job.enabled.Load() {
+		releaseIncoming([]*Incoming{item})
+		return errFlusherDisabled
+	}
+
+	// fibonacci hashing spreads sequential IDs across queues
+	index := uint32((item.ID*11400714819323198485)>>32) & job.queueMask
+	queue := job.queues[index]
+	if err := queue.Push(item); err != nil {
+		releaseIncoming([]*Incoming{item})
+		return err
 	}
-	return errFlusherDisabled
+	if queue.Length() >= job.queueFlushLength {
+		select {
+		case job.flushNow <- struct{}{}:
+		default:
+		}
+	}
+	return nil
 }
 
 func (job *Flusher) run(ctx context.Context) {
-	log.Println("Started background job")
+	job.log.Info("Started background job")
 
 	defer job.finish()
 
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			job.flush()
+			go job.tryFlush()
+			continue
+		case <-job.flushNow:
+			go job.tryFlush()
 			continue
 		case <-ctx.Done():
-			log.Println("Got cancel")
+			job.log.Info("Got cancel")
+			// waits for pushes in progress, they're part of the final flush
+			job.pushing.Lock()
 			job.enabled.Store(false)
+			job.pushing.Unlock()
+			// wait for a running flush to finish; the semaphore is
+			// never released, so pending tryFlush calls skip after
+			// the final flush and don't write to a closed sink
+			for !job.flushing.CanRun() {
+				time.Sleep(10 * time.Millisecond)
+			}
 			job.flush()
+			if err := job.sink.Close(); err != nil {
+				job.log.Error("Error when closing sink", "err", err)
+			}
 		}
 		break
 	}
 
-	log.Println("Exiting Run")
+	job.log.Info("Exiting Run")
 }
 
-func (job *Flusher) flush() {
-	var err error
+// tryFlush flushes the queues, unless a flush is already running
+func (job *Flusher) tryFlush() {
+	if !job.flushing.CanRun() {
+		if job.enabled.Load() {
+			job.log.Warn("Flush already running, skipping")
+		}
+		return
+	}
+	defer job.flushing.Done()
+	job.flush()
+}
 
-	fields := strings.Join(IncomingFields, ",")
-	named := ":" + strings.Join(IncomingFields, ",:")
-	query := fmt.Sprintf("insert into %s (%s) values (%s)", IncomingTable, fields, named)
+// flush drains all queues with a pool of workers
+func (job *Flusher) flush() {
+	start := time.Now()
 
-	var batchInsertSize int
+	queues := make(chan *Queue, len(job.queues))
 	for _, queue := range job.queues {
-		rows := queue.Clear()
-		for len(rows) > 0 {
-			batchInsertSize = 1000
-			if len(rows) < batchInsertSize {
-				batchInsertSize = len(rows)
-			}
-			if _, err = job.db.NamedExec(query, rows[:batchInsertSize]); err != nil {
-				log.Println("Error when flushing data:", err)
+		queues <- queue
+	}
+	close(queues)
+
+	var wg sync.WaitGroup
+	flushed := atomic.NewInt64(0)
+	for i := 0; i < job.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for queue := range queues {
+				flushed.Add(int64(job.flushQueue(queue)))
 			}
-			rows = rows[batchInsertSize:]
-		}
+		}()
+	}
+	wg.Wait()
+
+	if count := flushed.Load(); count > 0 {
+		job.log.Info("Flushed rows", "count", count, "duration", time.Since(start))
+	}
+}
+
+// flushQueue writes queued rows to the sink, returning the row count
+func (job *Flusher) flushQueue(queue *Queue) int {
+	rows := queue.Swap()
+	if len(rows) == 0 {
+		return 0
+	}
+	count := len(rows)
+	if err := job.sink.Write(rows); err != nil {
+		job.log.Error("Error when flushing data", "err", err, "rows", count)
 	}
+	releaseIncoming(rows)
+	return count
 }
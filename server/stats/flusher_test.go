@@ -0,0 +1,162 @@
+package stats
+
+import (
+	"context"
+	"io/ioutil"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twitchtv/twirp"
+	"go.uber.org/atomic"
+
+	"github.com/titpetric/microservice/config"
+)
+
+// closeCheckSink counts rows and writes after Close
+type closeCheckSink struct {
+	sync.Mutex
+	rows         int
+	closed       bool
+	writesClosed int
+}
+
+func (s *closeCheckSink) Write(rows []*Incoming) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		s.writesClosed++
+	}
+	s.rows += len(rows)
+	return nil
+}
+
+func (s *closeCheckSink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestFlusherShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sink := &closeCheckSink{}
+	job, err := NewFlusher(ctx, sink, slog.New(slog.NewTextHandler(ioutil.Discard, nil)), config.Flusher{})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating flusher: %+v", err)
+	}
+
+	if err := job.Push(&Incoming{ID: 1, Property: "news"}); err != nil {
+		t.Fatalf("Unexpected error on Push: %+v", err)
+	}
+
+	cancel()
+	<-job.Done()
+
+	// a pending flush from the ticker or flushNow runs after shutdown
+	job.queues[0].Push(&Incoming{ID: 2, Property: "news"})
+	job.tryFlush()
+
+	sink.Lock()
+	defer sink.Unlock()
+	if !sink.closed || sink.rows != 1 {
+		t.Errorf("Expected 1 flushed row and a closed sink, got %d rows, closed=%v", sink.rows, sink.closed)
+	}
+	if sink.writesClosed > 0 {
+		t.Errorf("Unexpected %d writes after sink.Close", sink.writesClosed)
+	}
+}
+
+func TestFlusherPushDuringShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sink := &closeCheckSink{}
+	job, err := NewFlusher(ctx, sink, slog.New(slog.NewTextHandler(ioutil.Discard, nil)), config.Flusher{})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating flusher: %+v", err)
+	}
+
+	// every accepted item is flushed, pushes racing the shutdown included
+	var wg sync.WaitGroup
+	accepted := atomic.NewInt64(0)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for id := uint64(i); ; id += 8 {
+				err := job.Push(&Incoming{ID: id, Property: "news"})
+				if err == errQueueFull {
+					continue
+				}
+				if err != nil {
+					if err != errFlusherDisabled {
+						t.Errorf("Unexpected error on Push: %+v", err)
+					}
+					return
+				}
+				accepted.Inc()
+			}
+		}(i)
+	}
+	for accepted.Load() < 1000 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	wg.Wait()
+	<-job.Done()
+
+	sink.Lock()
+	defer sink.Unlock()
+	if int64(sink.rows) != accepted.Load() {
+		t.Errorf("Expected %d flushed rows, got %d", accepted.Load(), sink.rows)
+	}
+	if sink.writesClosed > 0 {
+		t.Errorf("Unexpected %d writes after sink.Close", sink.writesClosed)
+	}
+}
+
+// blockingSink blocks writes until release is closed
+type blockingSink struct {
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(rows []*Incoming) error {
+	<-s.release
+	return nil
+}
+
+func (s *blockingSink) Close() error {
+	return nil
+}
+
+func TestFlusherQueueFull(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sink := &blockingSink{release: make(chan struct{})}
+	defer close(sink.release)
+	job, err := NewFlusher(ctx, sink, slog.New(slog.NewTextHandler(ioutil.Discard, nil)), config.Flusher{})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating flusher: %+v", err)
+	}
+
+	// items with the same ID go to the same queue; with the sink blocked,
+	// the queue fills up before the next flush
+	var pushed int
+	for ; pushed < 4*flusherQueueSize; pushed++ {
+		if err = job.Push(&Incoming{ID: 1, Property: "news"}); err != nil {
+			break
+		}
+	}
+	if err != errQueueFull {
+		t.Fatalf("Expected errQueueFull after %d items, got %+v", pushed, err)
+	}
+	if pushed < flusherQueueSize || pushed > 2*flusherQueueSize {
+		t.Errorf("Expected errQueueFull after %d to %d items, got %d", flusherQueueSize, 2*flusherQueueSize, pushed)
+	}
+
+	if twerr, ok := twirpError(err).(twirp.Error); !ok || twerr.Code() != twirp.ResourceExhausted {
+		t.Errorf("Expected twirp.ResourceExhausted, got %+v", twirpError(err))
+	}
+}
@@ -0,0 +1,24 @@
+package stats
+
+import (
+	"sync"
+)
+
+var incomingPool = sync.Pool{
+	New: func() interface{} {
+		return new(Incoming)
+	},
+}
+
+// NewIncoming returns a cleared *Incoming from a pool
+func NewIncoming() *Incoming {
+	return incomingPool.Get().(*Incoming)
+}
+
+// releaseIncoming returns rows to the pool after they have been flushed
+func releaseIncoming(rows []*Incoming) {
+	for _, row := range rows {
+		*row = Incoming{}
+		incomingPool.Put(row)
+	}
+}
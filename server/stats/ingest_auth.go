@@ -0,0 +1,164 @@
+package stats
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/twitchtv/twirp"
+
+	"github.com/titpetric/microservice/config"
+	"github.com/titpetric/microservice/internal"
+)
+
+// IngestAuth verifies ingestion credentials against IngestKeysTable
+//
+// Keys are cached and reloaded periodically, so keys can be rotated
+// without downtime: add a new key for the property, move callers to
+// it, and set `expires_at` on the old key.
+//
+// Pushes to properties without keys are allowed, unless
+// ingest.keys_required requires credentials for all properties.
+// Until keys are loaded, all pushes fail with twirp.Unavailable.
+type IngestAuth struct {
+	db  *sqlx.DB
+	log *slog.Logger
+
+	required bool
+	// refresh is the interval between key reloads
+	refresh time.Duration
+	// maxSkew limits the age of signed requests
+	maxSkew time.Duration
+
+	// keys holds an immutable *ingestKeySet, replaced on reload
+	keys atomic.Value
+}
+
+// ingestKeySet is a loaded snapshot of ingest keys
+type ingestKeySet struct {
+	byKeyID      map[string]*IngestKeys
+	byAPIKeyHash map[string]*IngestKeys
+	properties   map[string]bool
+}
+
+// NewIngestAuth creates an *IngestAuth, reloading keys until ctx is done
+func NewIngestAuth(ctx context.Context, db *sqlx.DB, log *slog.Logger, options config.Ingest) *IngestAuth {
+	auth := &IngestAuth{
+		db:       db,
+		log:      log,
+		required: options.KeysRequired,
+		refresh:  30 * time.Second,
+		maxSkew:  5 * time.Minute,
+	}
+	if err := auth.load(ctx); err != nil {
+		log.ErrorContext(ctx, "Error loading ingest keys", "err", err)
+	}
+	go auth.run(ctx)
+	return auth
+}
+
+// run reloads keys in the background
+func (auth *IngestAuth) run(ctx context.Context) {
+	ticker := time.NewTicker(auth.refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := auth.load(ctx); err != nil {
+				auth.log.ErrorContext(ctx, "Error loading ingest keys", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Verify checks that the credentials in ctx allow pushing to property
+func (auth *IngestAuth) Verify(ctx context.Context, property string) error {
+	keys, _ := auth.keys.Load().(*ingestKeySet)
+	if keys == nil {
+		return twirp.NewError(twirp.Unavailable, "ingest keys unavailable")
+	}
+
+	credentials := internal.GetCredentialsFromContext(ctx)
+	if credentials.Empty() {
+		if auth.required || keys.properties[property] {
+			return twirp.NewError(twirp.Unauthenticated, "missing ingest credentials")
+		}
+		return nil
+	}
+
+	key, ok := auth.lookup(keys, credentials)
+	if !ok || key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
+		return twirp.NewError(twirp.Unauthenticated, "invalid ingest credentials")
+	}
+	if key.Property != property {
+		return twirp.NewError(twirp.PermissionDenied, "ingest key not valid for property")
+	}
+	return nil
+}
+
+// lookup finds the key matching credentials, verifying signatures
+func (auth *IngestAuth) lookup(keys *ingestKeySet, credentials internal.Credentials) (*IngestKeys, bool) {
+	if credentials.APIKey != "" {
+		hash := hashAPIKey(credentials.APIKey)
+		// keys are indexed by hash, so lookup timing doesn't depend on the API key
+		key, ok := keys.byAPIKeyHash[hash]
+		if !ok || subtle.ConstantTimeCompare([]byte(key.APIKeyHash), []byte(hash)) != 1 {
+			return nil, false
+		}
+		return key, true
+	}
+
+	key, ok := keys.byKeyID[credentials.KeyID]
+	if !ok || key.Secret == "" {
+		return nil, false
+	}
+	timestamp, err := strconv.ParseInt(credentials.Timestamp, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	if skew := time.Since(time.Unix(timestamp, 0)); skew > auth.maxSkew || skew < -auth.maxSkew {
+		return nil, false
+	}
+	return key, credentials.VerifySignature(key.Secret)
+}
+
+// load reads all keys, expired keys are rejected on use
+func (auth *IngestAuth) load(ctx context.Context) error {
+	rows := []*IngestKeys{}
+	if err := auth.db.SelectContext(ctx, &rows, "select * from ingest_keys"); err != nil {
+		return internal.DBError(errors.WithStack(err))
+	}
+
+	keys := &ingestKeySet{
+		byKeyID:      make(map[string]*IngestKeys, len(rows)),
+		byAPIKeyHash: make(map[string]*IngestKeys, len(rows)),
+		properties:   make(map[string]bool),
+	}
+	for _, key := range rows {
+		key.APIKeyHash = strings.ToLower(key.APIKeyHash)
+		keys.byKeyID[key.KeyID] = key
+		if key.APIKeyHash != "" {
+			keys.byAPIKeyHash[key.APIKeyHash] = key
+		}
+		keys.properties[key.Property] = true
+	}
+	auth.keys.Store(keys)
+	return nil
+}
+
+// hashAPIKey returns the hex encoded SHA-256 of an API key, as stored in `api_key_hash`
+func hashAPIKey(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])
+}
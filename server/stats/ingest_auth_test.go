@@ -0,0 +1,118 @@
+// +build cgo
+
+package stats
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"net/url"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/titpetric/microservice/config"
+	"github.com/titpetric/microservice/db"
+	"github.com/titpetric/microservice/internal"
+)
+
+func TestIngestAuth(t *testing.T) {
+	handle := newSinkTestDB(t)
+	defer handle.Close()
+
+	key := &IngestKeys{KeyID: "secure-1", Property: "secure", Secret: "s3cret", APIKeyHash: hashAPIKey("ap1key")}
+	key.SetCreatedAt(time.Now())
+	if _, err := handle.NamedExec("insert into ingest_keys (key_id, property, secret, api_key_hash, created_at) values (:key_id, :property, :secret, :api_key_hash, :created_at)", key); err != nil {
+		t.Fatalf("Unexpected error inserting key: %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	auth := NewIngestAuth(ctx, handle, slog.Default(), config.Ingest{})
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	body := []byte("payload")
+	target, _ := url.Parse("/twirp/stats.StatsService/Push")
+	signed := func(secret string) internal.Credentials {
+		hash := sha256.Sum256(body)
+		return internal.Credentials{
+			KeyID:     "secure-1",
+			Timestamp: timestamp,
+			Signature: internal.Sign(secret, "POST", target, timestamp, body),
+			Method:    "POST",
+			Path:      target.Path,
+			BodyHash:  hex.EncodeToString(hash[:]),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		property    string
+		credentials internal.Credentials
+		code        twirp.ErrorCode
+	}{
+		{"open property", "news", internal.Credentials{}, twirp.NoError},
+		{"missing credentials", "secure", internal.Credentials{}, twirp.Unauthenticated},
+		{"api key", "secure", internal.Credentials{APIKey: "ap1key"}, twirp.NoError},
+		{"invalid api key", "secure", internal.Credentials{APIKey: "guess"}, twirp.Unauthenticated},
+		{"signing secret as api key", "secure", internal.Credentials{APIKey: "s3cret"}, twirp.Unauthenticated},
+		{"other property", "news", internal.Credentials{APIKey: "ap1key"}, twirp.PermissionDenied},
+		{"signature", "secure", signed("s3cret"), twirp.NoError},
+		{"invalid signature", "secure", signed("guess"), twirp.Unauthenticated},
+	}
+	for _, test := range tests {
+		err := auth.Verify(internal.SetCredentialsToContext(context.Background(), test.credentials), test.property)
+		code := twirp.NoError
+		if twerr, ok := err.(twirp.Error); ok {
+			code = twerr.Code()
+		}
+		if code != test.code {
+			t.Errorf("%s: expected %q, got %+v", test.name, test.code, err)
+		}
+	}
+
+	// with required keys, open properties need credentials
+	requiredAuth := NewIngestAuth(ctx, handle, slog.Default(), config.Ingest{KeysRequired: true})
+	err := requiredAuth.Verify(context.Background(), "news")
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.Unauthenticated {
+		t.Errorf("Expected twirp.Unauthenticated with required keys, got %+v", err)
+	}
+
+	// rotated keys are rejected after expiry
+	if _, err := handle.Exec("update ingest_keys set expires_at=? where key_id=?", time.Now().Add(-time.Second), "secure-1"); err != nil {
+		t.Fatalf("Unexpected error expiring key: %+v", err)
+	}
+	if err := auth.load(ctx); err != nil {
+		t.Fatalf("Unexpected error reloading keys: %+v", err)
+	}
+	err = auth.Verify(internal.SetCredentialsToContext(context.Background(), internal.Credentials{APIKey: "ap1key"}), "secure")
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.Unauthenticated {
+		t.Errorf("Expected twirp.Unauthenticated for expired key, got %+v", err)
+	}
+}
+
+func TestIngestAuthUnavailable(t *testing.T) {
+	options := db.ConnectionOptions{}
+	options.Credentials.Driver = "sqlite"
+	options.Credentials.DSN = ":memory:"
+
+	// without migrations, loading keys fails
+	handle, err := db.ConnectWithOptions(context.Background(), options)
+	if err != nil {
+		t.Fatalf("Unexpected error when connecting: %+v", err)
+	}
+	defer handle.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	auth := NewIngestAuth(ctx, handle, slog.Default(), config.Ingest{})
+	err = auth.Verify(context.Background(), "news")
+	if twerr, ok := err.(twirp.Error); !ok || twerr.Code() != twirp.Unavailable {
+		t.Errorf("Expected twirp.Unavailable before keys are loaded, got %+v", err)
+	}
+}
@@ -1,50 +1,116 @@
 package stats
 
 import (
-	"sync"
+	"runtime"
+	"sync/atomic"
+
+	uatomic "go.uber.org/atomic"
 )
 
-// Queue provides a queuing structure for Incoming{}
+// Queue provides a double buffered, lock-free queuing structure for Incoming{}
+//
+// Writers reserve a slot in the active buffer with an atomic increment.
+// A single reader calls Swap, which replaces the active buffer with a spare
+// one and returns the contents of the previous buffer without copying.
 type Queue struct {
-	sync.RWMutex
+	active atomic.Value
+	spare  *queueBuffer
+}
+
+type queueBuffer struct {
+	// values is a fixed size slice for queue items
 	values []*Incoming
+	// length is the number of reserved slots, may exceed len(values)
+	length *uatomic.Uint32
+	// writers is the number of writers currently using the buffer
+	writers *uatomic.Int32
+}
+
+func newQueueBuffer(size int) *queueBuffer {
+	return &queueBuffer{
+		values:  make([]*Incoming, size),
+		length:  uatomic.NewUint32(0),
+		writers: uatomic.NewInt32(0),
+	}
+}
+
+// Len returns the number of written values
+func (b *queueBuffer) Len() int {
+	if length := int(b.length.Load()); length < len(b.values) {
+		return length
+	}
+	return len(b.values)
 }
 
-// NewQueue creates a new *Queue instance
-func NewQueue() *Queue {
-	return &Queue{
-		values: make([]*Incoming, 0),
+// reset clears references to values and the length
+func (b *queueBuffer) reset() {
+	for k := range b.values[:b.Len()] {
+		b.values[k] = nil
 	}
+	b.length.Store(0)
+}
+
+// NewQueue creates a new *Queue instance, holding up to size items
+func NewQueue(size int) *Queue {
+	queue := &Queue{
+		spare: newQueueBuffer(size),
+	}
+	queue.active.Store(newQueueBuffer(size))
+	return queue
 }
 
 // NewQueues creates a slice of *Queue instances
-func NewQueues(size int) []*Queue {
-	result := make([]*Queue, size)
-	for i := 0; i < size; i++ {
-		result[i] = NewQueue()
+func NewQueues(count, size int) []*Queue {
+	result := make([]*Queue, count)
+	for i := 0; i < count; i++ {
+		result[i] = NewQueue(size)
 	}
 	return result
 }
 
 // Push adds a new item to the queue
 func (p *Queue) Push(item *Incoming) error {
-	p.Lock()
-	defer p.Unlock()
-	p.values = append(p.values, item)
-	return nil
+	for {
+		buffer := p.active.Load().(*queueBuffer)
+		buffer.writers.Inc()
+		// the buffer was swapped out before we registered as a writer
+		if p.active.Load().(*queueBuffer) != buffer {
+			buffer.writers.Dec()
+			continue
+		}
+
+		index := int(buffer.length.Inc()) - 1
+		if index >= len(buffer.values) {
+			buffer.writers.Dec()
+			return errQueueFull
+		}
+		buffer.values[index] = item
+		buffer.writers.Dec()
+		return nil
+	}
 }
 
-// Clear returns current queue items and clears it
-func (p *Queue) Clear() (result []*Incoming) {
-	p.Lock()
-	defer p.Unlock()
-	result, p.values = p.values[:len(p.values)], p.values[len(p.values):]
-	return
+// Swap returns current queue items and clears it
+//
+// The returned slice is valid until the next call to Swap. Swap
+// must not be called concurrently for the same queue.
+func (p *Queue) Swap() []*Incoming {
+	next := p.spare
+	next.reset()
+
+	buffer := p.active.Load().(*queueBuffer)
+	p.active.Store(next)
+
+	// wait for writers which have reserved a slot before the swap
+	for buffer.writers.Load() > 0 {
+		runtime.Gosched()
+	}
+
+	p.spare = buffer
+	return buffer.values[:buffer.Len()]
 }
 
 // Length returns the current queue size
 func (p *Queue) Length() int {
-	p.RLock()
-	defer p.RUnlock()
-	return len(p.values)
+	return p.active.Load().(*queueBuffer).Len()
 }
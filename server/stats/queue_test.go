@@ -1,7 +1,15 @@
 package stats
 
 import (
+	"context"
+	"io/ioutil"
+	"log/slog"
+	"sync"
 	"testing"
+
+	"go.uber.org/atomic"
+
+	"github.com/titpetric/microservice/config"
 )
 
 func TestQueue(t *testing.T) {
@@ -11,7 +19,7 @@ func TestQueue(t *testing.T) {
 		}
 	}
 
-	queue := NewQueue()
+	queue := NewQueue(4)
 	assert(queue.Length() == 0, "Unexpected queue length: %d != 0", queue.Length())
 
 	assert(nil == queue.Push(new(Incoming)), "Expected no error on queue.Push")
@@ -20,13 +28,106 @@ func TestQueue(t *testing.T) {
 
 	assert(queue.Length() == 3, "Unexpected queue length: %d != 3", queue.Length())
 
-	items := queue.Clear()
+	items := queue.Swap()
 	assert(len(items) == 3, "Unexpected items length: %d != 3", len(items))
 	assert(queue.Length() == 0, "Unexpected queue length: %d != 0", queue.Length())
 
-	queues := NewQueues(16)
+	for i := 0; i < 4; i++ {
+		assert(nil == queue.Push(new(Incoming)), "Expected no error on queue.Push")
+	}
+	assert(errQueueFull == queue.Push(new(Incoming)), "Expected errQueueFull on queue.Push")
+	assert(queue.Length() == 4, "Unexpected queue length: %d != 4", queue.Length())
+
+	items = queue.Swap()
+	assert(len(items) == 4, "Unexpected items length: %d != 4", len(items))
+
+	queues := NewQueues(16, 4)
 	assert(len(queues) == 16, "Unexpected queue count: %d != 16", len(queues))
 	for k, v := range queues {
 		assert(v != nil, "Unexpected queue value: expected not nil, index %d", k)
 	}
 }
+
+func TestQueueConcurrent(t *testing.T) {
+	queue := NewQueue(1 << 16)
+
+	var wg sync.WaitGroup
+	writers, count := 8, 1000
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < count; j++ {
+				if err := queue.Push(new(Incoming)); err != nil {
+					t.Errorf("Unexpected error on queue.Push: %+v", err)
+				}
+			}
+		}()
+	}
+
+	total := 0
+	done := atomic.NewBool(false)
+	go func() {
+		wg.Wait()
+		done.Store(true)
+	}()
+	for !done.Load() {
+		total += len(queue.Swap())
+	}
+	total += len(queue.Swap())
+
+	if total != writers*count {
+		t.Fatalf("Unexpected item count: %d != %d", total, writers*count)
+	}
+}
+
+func BenchmarkFlusherPush(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	job, _ := NewFlusher(ctx, NewWriterSink(ioutil.Discard), slog.New(slog.NewTextHandler(ioutil.Discard, nil)), config.Flusher{})
+
+	id := atomic.NewUint64(0)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			item := NewIncoming()
+			item.ID = id.Inc()
+			job.Push(item)
+		}
+	})
+}
+
+// mutexQueue is the previous mutex guarded, unbounded Queue, as a benchmark baseline
+type mutexQueue struct {
+	sync.Mutex
+	values []*Incoming
+}
+
+func (p *mutexQueue) Push(item *Incoming) error {
+	p.Lock()
+	defer p.Unlock()
+	p.values = append(p.values, item)
+	return nil
+}
+
+// BenchmarkMutexQueuePush is the baseline for BenchmarkFlusherPush
+//
+// It pushes to the same number of queues, with a mutex and append.
+func BenchmarkMutexQueuePush(b *testing.B) {
+	queues := make([]*mutexQueue, flusherQueueCount)
+	for i := range queues {
+		queues[i] = new(mutexQueue)
+	}
+	mask := uint64(flusherQueueCount - 1)
+
+	id := atomic.NewUint64(0)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			item := NewIncoming()
+			item.ID = id.Inc()
+			queues[item.ID&mask].Push(item)
+		}
+	})
+}
@@ -1,9 +1,14 @@
 package stats
 
 import (
+	"log/slog"
+
+	"net/http"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/sony/sonyflake"
 
+	"github.com/titpetric/microservice/internal"
 	"github.com/titpetric/microservice/rpc/stats"
 )
 
@@ -11,13 +16,30 @@ import (
 type Server struct {
 	db *sqlx.DB
 
-	sonyflake *sonyflake.Sonyflake
-	flusher   *Flusher
+	sonyflake  *sonyflake.Sonyflake
+	flusher    *Flusher
+	ingestAuth *IngestAuth
+	origins    internal.CORSOrigins
+}
+
+// AuthRules declare the authorization for RPC methods, Push is public
+var AuthRules = internal.AuthRules{
+	"stats.StatsService/Push": {Public: true},
+	"pixel.gif":               {Public: true},
+	"beacon":                  {Public: true},
+	"*":                       {Scopes: []string{"stats:admin"}},
+}
+
+// RegisterHandlers mounts HTTP endpoints next to the RPC handlers
+func RegisterHandlers(mux *http.ServeMux, service stats.StatsService, log *slog.Logger) {
+	mux.Handle("/pixel.gif", NewPixelHandler(service, log))
+	mux.Handle("/beacon", NewBeaconHandler(service, log))
 }
 
 // Shutdown is a cleanup hook after SIGTERM
 func (svc *Server) Shutdown() {
 	<-svc.flusher.Done()
+	svc.db.Close()
 }
 
 var _ stats.StatsService = &Server{}
@@ -0,0 +1,163 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"mime"
+	"strconv"
+
+	"net/http"
+
+	"go.uber.org/atomic"
+
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+const (
+	// beaconMaxBodySize limits the beacon request body
+	beaconMaxBodySize = 64 << 10
+	// beaconMaxEvents limits the number of events in a beacon
+	beaconMaxEvents = 100
+)
+
+var errBeaconTooManyEvents = errors.New("too many events in beacon")
+
+// BeaconHandler accepts navigator.sendBeacon requests as one or more Push calls
+//
+// A text/plain body holds a JSON event or an array of JSON events, e.g.
+// `[{"property": "news", "section": 1, "id": 10}]`. Form bodies, url-encoded
+// or multipart, repeat the property, section and id fields for each event.
+// Invalid events are skipped and counted as rejected.
+type BeaconHandler struct {
+	service stats.StatsService
+	log     *slog.Logger
+
+	accepted *atomic.Uint64
+	rejected *atomic.Uint64
+}
+
+// NewBeaconHandler creates a *BeaconHandler, service should be the validating service
+func NewBeaconHandler(service stats.StatsService, log *slog.Logger) *BeaconHandler {
+	return &BeaconHandler{
+		service:  service,
+		log:      log,
+		accepted: atomic.NewUint64(0),
+		rejected: atomic.NewUint64(0),
+	}
+}
+
+// Accepted returns the number of pushed events
+func (b *BeaconHandler) Accepted() uint64 {
+	return b.accepted.Load()
+}
+
+// Rejected returns the number of invalid or failed events
+func (b *BeaconHandler) Rejected() uint64 {
+	return b.rejected.Load()
+}
+
+// ServeHTTP implements http.Handler
+func (b *BeaconHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, beaconMaxBodySize)
+	events, err := b.decode(r)
+	if err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		b.log.WarnContext(r.Context(), "Rejected beacon request", "err", err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	ctx := r.Context()
+	for _, event := range events {
+		if _, err := b.service.Push(ctx, event); err != nil {
+			total := b.rejected.Inc()
+			b.log.WarnContext(ctx, "Rejected beacon event", "err", err, "rejected", total)
+			continue
+		}
+		b.accepted.Inc()
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *BeaconHandler) decode(r *http.Request) ([]*stats.PushRequest, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		// sendBeacon with a string body sends text/plain, default to it
+		mediaType = "text/plain"
+	}
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		return b.decodeForm(r)
+	case "text/plain", "application/json":
+		return b.decodeJSON(r)
+	}
+	return nil, errors.New("unsupported content type: " + mediaType)
+}
+
+func (b *BeaconHandler) decodeJSON(r *http.Request) ([]*stats.PushRequest, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*stats.PushRequest
+	if err := json.Unmarshal(body, &events); err != nil {
+		event := new(stats.PushRequest)
+		if err := json.Unmarshal(body, event); err != nil {
+			return nil, err
+		}
+		events = []*stats.PushRequest{event}
+	}
+	if len(events) > beaconMaxEvents {
+		return nil, errBeaconTooManyEvents
+	}
+	return events, nil
+}
+
+func (b *BeaconHandler) decodeForm(r *http.Request) ([]*stats.PushRequest, error) {
+	if err := r.ParseMultipartForm(beaconMaxBodySize); err != nil && err != http.ErrNotMultipart {
+		return nil, err
+	}
+
+	properties, sections, ids := r.PostForm["property"], r.PostForm["section"], r.PostForm["id"]
+	if len(properties) > beaconMaxEvents {
+		return nil, errBeaconTooManyEvents
+	}
+
+	events := make([]*stats.PushRequest, 0, len(properties))
+	for i, property := range properties {
+		if i >= len(sections) || i >= len(ids) {
+			b.rejected.Inc()
+			continue
+		}
+		section, err := strconv.ParseUint(sections[i], 10, 32)
+		if err != nil {
+			b.rejected.Inc()
+			continue
+		}
+		id, err := strconv.ParseUint(ids[i], 10, 32)
+		if err != nil {
+			b.rejected.Inc()
+			continue
+		}
+		events = append(events, &stats.PushRequest{
+			Property: property,
+			Section:  uint32(section),
+			Id:       uint32(id),
+		})
+	}
+	return events, nil
+}
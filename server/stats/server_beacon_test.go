@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+func TestBeaconHandler(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		accepted    uint64
+		rejected    uint64
+	}{
+		{"json object", "text/plain;charset=UTF-8", `{"property":"news","section":1,"id":10}`, http.StatusNoContent, 1, 0},
+		{"json array", "text/plain", `[{"property":"news","section":1,"id":10},{"property":"news","section":0,"id":11},{"property":"news","section":2,"id":12}]`, http.StatusNoContent, 2, 1},
+		{"form", "application/x-www-form-urlencoded", "property=news&section=1&id=10&property=news&section=x&id=11", http.StatusNoContent, 1, 1},
+		{"invalid json", "text/plain", `{"property":`, http.StatusBadRequest, 0, 0},
+		{"too large", "text/plain", `"` + strings.Repeat("x", beaconMaxBodySize) + `"`, http.StatusRequestEntityTooLarge, 0, 0},
+		{"unsupported", "image/png", "", http.StatusBadRequest, 0, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc := &pixelTestService{}
+			handler := NewBeaconHandler(stats.NewStatsServiceValidator(svc), slog.Default())
+
+			req := httptest.NewRequest("POST", "/beacon", strings.NewReader(c.body))
+			req.Header.Set("Content-Type", c.contentType)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != c.status {
+				t.Errorf("Expected status %d, got %d", c.status, w.Code)
+			}
+			if handler.Accepted() != c.accepted || handler.Rejected() != c.rejected {
+				t.Errorf("Expected %d accepted and %d rejected, got %d and %d", c.accepted, c.rejected, handler.Accepted(), handler.Rejected())
+			}
+			if uint64(len(svc.pushed)) != c.accepted {
+				t.Errorf("Expected %d pushed events, got %d", c.accepted, len(svc.pushed))
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package stats
+
+import (
+	"log/slog"
+	"strconv"
+
+	"net/http"
+
+	"go.uber.org/atomic"
+
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+// pixelGIF is a 1x1 transparent GIF
+var pixelGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0xff, 0xff, 0xff,
+	0x00, 0x00, 0x00, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// PixelHandler serves GET /pixel.gif?property=..&section=..&id=.. as a Push
+//
+// The pixel is returned for invalid requests too, so pages never show a
+// broken image. Those requests are counted and logged as rejected. HEAD
+// requests from link checkers and probes get the headers, without a Push.
+type PixelHandler struct {
+	service stats.StatsService
+	log     *slog.Logger
+
+	accepted *atomic.Uint64
+	rejected *atomic.Uint64
+}
+
+// NewPixelHandler creates a *PixelHandler, service should be the validating service
+func NewPixelHandler(service stats.StatsService, log *slog.Logger) *PixelHandler {
+	return &PixelHandler{
+		service:  service,
+		log:      log,
+		accepted: atomic.NewUint64(0),
+		rejected: atomic.NewUint64(0),
+	}
+}
+
+// Accepted returns the number of pushed requests
+func (p *PixelHandler) Accepted() uint64 {
+	return p.accepted.Load()
+}
+
+// Rejected returns the number of invalid or failed requests
+func (p *PixelHandler) Rejected() uint64 {
+	return p.rejected.Load()
+}
+
+// ServeHTTP implements http.Handler
+func (p *PixelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		if err := p.push(r); err != nil {
+			total := p.rejected.Inc()
+			p.log.WarnContext(r.Context(), "Rejected pixel request", "err", err, "rejected", total)
+		} else {
+			p.accepted.Inc()
+		}
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "image/gif")
+	header.Set("Content-Length", strconv.Itoa(len(pixelGIF)))
+	header.Set("Cache-Control", "no-cache, no-store, must-revalidate, max-age=0")
+	header.Set("Pragma", "no-cache")
+	header.Set("Expires", "0")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(pixelGIF)
+	}
+}
+
+func (p *PixelHandler) push(r *http.Request) error {
+	query := r.URL.Query()
+	section, err := strconv.ParseUint(query.Get("section"), 10, 32)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(query.Get("id"), 10, 32)
+	if err != nil {
+		return err
+	}
+	_, err = p.service.Push(r.Context(), &stats.PushRequest{
+		Property: query.Get("property"),
+		Section:  uint32(section),
+		Id:       uint32(id),
+	})
+	return err
+}
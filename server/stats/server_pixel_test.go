@@ -0,0 +1,79 @@
+package stats
+
+import (
+	"bytes"
+	"context"
+	"image/gif"
+	"log/slog"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+type pixelTestService struct {
+	pushed []*stats.PushRequest
+}
+
+func (s *pixelTestService) Push(_ context.Context, r *stats.PushRequest) (*stats.PushResponse, error) {
+	s.pushed = append(s.pushed, r)
+	return new(stats.PushResponse), nil
+}
+
+func TestPixelHandler(t *testing.T) {
+	svc := &pixelTestService{}
+	handler := NewPixelHandler(stats.NewStatsServiceValidator(svc), slog.Default())
+
+	for _, query := range []string{
+		"property=news&section=1&id=10",
+		"property=news&section=0&id=10",
+		"property=news&section=x&id=10",
+		"property=&section=1&id=10",
+		"section=1",
+	} {
+		req := httptest.NewRequest("GET", "/pixel.gif?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/gif" {
+			t.Fatalf("Expected pixel for %q, got status %d, content type %q", query, w.Code, w.Header().Get("Content-Type"))
+		}
+		if w.Header().Get("Cache-Control") == "" {
+			t.Errorf("Expected no-cache headers for %q", query)
+		}
+		img, err := gif.Decode(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("Unexpected error decoding pixel: %+v", err)
+		}
+		if size := img.Bounds().Size(); size.X != 1 || size.Y != 1 {
+			t.Errorf("Expected 1x1 pixel, got %v", size)
+		}
+	}
+
+	if len(svc.pushed) != 1 || svc.pushed[0].Property != "news" || svc.pushed[0].Id != 10 {
+		t.Errorf("Expected a single valid push, got %v", svc.pushed)
+	}
+	if handler.Accepted() != 1 || handler.Rejected() != 4 {
+		t.Errorf("Expected 1 accepted and 4 rejected, got %d and %d", handler.Accepted(), handler.Rejected())
+	}
+
+	// HEAD gets headers only, without recording a view
+	req := httptest.NewRequest("HEAD", "/pixel.gif?property=news&section=1&id=10", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/gif" || w.Body.Len() != 0 {
+		t.Errorf("Expected pixel headers for HEAD, got status %d, %d bytes", w.Code, w.Body.Len())
+	}
+	if len(svc.pushed) != 1 || handler.Accepted() != 1 {
+		t.Errorf("Expected no push for HEAD, got %d pushes", len(svc.pushed))
+	}
+
+	req = httptest.NewRequest("POST", "/pixel.gif", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for POST, got %d", w.Code)
+	}
+}
@@ -2,9 +2,10 @@ package stats
 
 import (
 	"context"
-	"errors"
 	"time"
 
+	"github.com/twitchtv/twirp"
+
 	"github.com/titpetric/microservice/internal"
 	"github.com/titpetric/microservice/rpc/stats"
 )
@@ -13,34 +14,28 @@ import (
 var pushResponseDefault = new(stats.PushResponse)
 
 // Push a record to the incoming log table
+//
+// Request validation rules are declared in rpc/stats/stats.proto,
+// and checked by stats.NewStatsServiceValidator before Push is called.
+// Ingestion credentials for the property are verified by IngestAuth,
+// browser requests must come from an origin allowed for the property.
 func (svc *Server) Push(ctx context.Context, r *stats.PushRequest) (*stats.PushResponse, error) {
 	ctx = internal.ContextWithoutCancel(ctx)
 
-	validate := func() error {
-		if r.Property == "" {
-			return errors.New("missing property")
-		}
-		if r.Property != "news" {
-			return errors.New("invalid property")
-		}
-		if r.Id < 1 {
-			return errors.New("missing id")
-		}
-		if r.Section < 1 {
-			return errors.New("missing section")
-		}
-		return nil
-	}
-	if err := validate(); err != nil {
+	if err := svc.ingestAuth.Verify(ctx, r.Property); err != nil {
 		return nil, err
 	}
+	if origin := internal.GetOriginFromContext(ctx); origin != "" && !svc.origins.Allowed(r.Property, origin) {
+		return nil, twirp.NewError(twirp.PermissionDenied, "origin not allowed for property")
+	}
 
 	var err error
-	row := new(Incoming)
+	row := NewIncoming()
 
 	row.ID, err = svc.sonyflake.NextID()
 	if err != nil {
-		return nil, err
+		releaseIncoming([]*Incoming{row})
+		return nil, twirp.NewError(twirp.Internal, "error generating ID")
 	}
 
 	row.Property = r.Property
@@ -49,5 +44,8 @@ func (svc *Server) Push(ctx context.Context, r *stats.PushRequest) (*stats.PushR
 	row.RemoteIP = internal.GetIPFromContext(ctx)
 	row.SetStamp(time.Now())
 
-	return pushResponseDefault, svc.flusher.Push(row)
+	if err := svc.flusher.Push(row); err != nil {
+		return nil, twirpError(err)
+	}
+	return pushResponseDefault, nil
 }
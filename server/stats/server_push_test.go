@@ -0,0 +1,84 @@
+//go:build cgo
+// +build cgo
+
+package stats
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/sony/sonyflake"
+	"github.com/twitchtv/twirp"
+
+	"github.com/titpetric/microservice/config"
+	"github.com/titpetric/microservice/db"
+	"github.com/titpetric/microservice/internal"
+	"github.com/titpetric/microservice/rpc/stats"
+)
+
+func TestServerPush(t *testing.T) {
+	assert := func(ok bool, format string, params ...interface{}) {
+		if !ok {
+			t.Fatalf(format, params...)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	options := db.ConnectionOptions{}
+	options.Credentials.Driver = "sqlite"
+	options.Credentials.DSN = ":memory:"
+
+	handle, err := db.ConnectWithOptions(ctx, options)
+	assert(err == nil, "Unexpected error when connecting: %+v", err)
+	defer handle.Close()
+
+	err = db.Run("stats", handle)
+	assert(err == nil, "Unexpected error when running migrations: %+v", err)
+
+	flusher, err := NewFlusher(ctx, NewDatabaseSink(handle), slog.Default(), config.Flusher{})
+	assert(err == nil, "Unexpected error when creating flusher: %+v", err)
+
+	svc := &Server{
+		db:         handle,
+		flusher:    flusher,
+		ingestAuth: NewIngestAuth(ctx, handle, slog.Default(), config.Ingest{}),
+		origins:    internal.CORSOrigins{"news": {"https://news.example.com"}},
+		sonyflake: sonyflake.NewSonyflake(sonyflake.Settings{
+			MachineID: func() (uint16, error) {
+				return 1, nil
+			},
+		}),
+	}
+
+	// validation rules are checked by the generated wrapper
+	validator := stats.NewStatsServiceValidator(svc)
+
+	for i := uint32(1); i <= 3; i++ {
+		_, err := validator.Push(ctx, &stats.PushRequest{Property: "news", Section: 1, Id: i})
+		assert(err == nil, "Unexpected error on Push: %+v", err)
+	}
+	_, err = validator.Push(ctx, &stats.PushRequest{Property: "news"})
+	twerr, ok := err.(twirp.Error)
+	assert(ok && twerr.Code() == twirp.InvalidArgument, "Expected twirp.InvalidArgument on Push with missing section, got %+v", err)
+
+	_, err = validator.Push(internal.SetOriginToContext(ctx, "https://news.example.com"), &stats.PushRequest{Property: "news", Section: 1, Id: 3})
+	assert(err == nil, "Unexpected error on Push from allowed origin: %+v", err)
+	_, err = validator.Push(internal.SetOriginToContext(ctx, "https://sport.example.com"), &stats.PushRequest{Property: "news", Section: 1, Id: 3})
+	twerr, ok = err.(twirp.Error)
+	assert(ok && twerr.Code() == twirp.PermissionDenied, "Expected twirp.PermissionDenied on Push from other origin, got %+v", err)
+
+	cancel()
+	<-flusher.Done()
+
+	_, err = svc.Push(ctx, &stats.PushRequest{Property: "news", Section: 1, Id: 4})
+	twerr, ok = err.(twirp.Error)
+	assert(ok && twerr.Code() == twirp.Unavailable, "Expected twirp.Unavailable on Push after shutdown, got %+v", err)
+
+	var count int
+	err = handle.Get(&count, "select count(*) from incoming")
+	assert(err == nil, "Unexpected error when counting rows: %+v", err)
+	assert(count == 4, "Unexpected row count: %d != 4", count)
+}
@@ -0,0 +1,61 @@
+package stats
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/titpetric/microservice/config"
+)
+
+// Sink is a destination for rows flushed from the queues
+type Sink interface {
+	// Write stores a batch of rows
+	Write(rows []*Incoming) error
+	// Close releases any resources held by the sink
+	Close() error
+}
+
+// NewSink creates a Sink based on the flusher config
+//
+// The sink is a comma separated list of `db`, `db-bulk`, `jsonl` and
+// `stdout`, defaulting to `db`. Listing more than one sink writes to all
+// of them.
+func NewSink(db *sqlx.DB, options config.Flusher) (Sink, error) {
+	names := options.Sink
+	if names == "" {
+		names = "db"
+	}
+
+	sinks := []Sink{}
+	for _, name := range strings.Split(names, ",") {
+		sink, err := newSink(strings.TrimSpace(name), db, options.SinkPath)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return NewTeeSink(sinks...), nil
+}
+
+func newSink(name string, db *sqlx.DB, path string) (Sink, error) {
+	switch name {
+	case "db":
+		return NewDatabaseSink(db), nil
+	case "db-bulk":
+		return NewBulkDatabaseSink(db), nil
+	case "jsonl":
+		if path == "" {
+			path = "data"
+		}
+		return NewJSONLSink(path)
+	case "stdout":
+		return NewWriterSink(os.Stdout), nil
+	}
+	return nil, errors.Errorf("Unknown flusher sink: '%s'", name)
+}
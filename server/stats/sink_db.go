@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/titpetric/microservice/db"
+	"github.com/titpetric/microservice/internal"
+)
+
+// DatabaseSink writes rows into IncomingTable with batched inserts
+type DatabaseSink struct {
+	db        *sqlx.DB
+	query     string
+	batchSize int
+}
+
+// NewDatabaseSink creates a *DatabaseSink
+func NewDatabaseSink(handle *sqlx.DB) *DatabaseSink {
+	fields := strings.Join(IncomingFields, ",")
+	named := ":" + strings.Join(IncomingFields, ",:")
+	dialect := db.DialectFor(handle.DriverName())
+
+	return &DatabaseSink{
+		db:        handle,
+		query:     fmt.Sprintf("insert into %s (%s) values (%s)", dialect.Quote(IncomingTable), fields, named),
+		batchSize: maxBatchRows(dialect, len(IncomingFields)),
+	}
+}
+
+// Write inserts rows in batches of up to 1000 rows
+func (sink *DatabaseSink) Write(rows []*Incoming) error {
+	var batchInsertSize int
+	for len(rows) > 0 {
+		batchInsertSize = sink.batchSize
+		if len(rows) < batchInsertSize {
+			batchInsertSize = len(rows)
+		}
+		if _, err := sink.db.NamedExec(sink.query, rows[:batchInsertSize]); err != nil {
+			return internal.DBError(err)
+		}
+		rows = rows[batchInsertSize:]
+	}
+	return nil
+}
+
+// Close is a no-op, the database handle is shared
+func (*DatabaseSink) Close() error {
+	return nil
+}
+
+// maxBatchRows returns the rows per insert for rows of fields columns
+//
+// sqlite is limited to 999 bound parameters per query.
+func maxBatchRows(dialect db.Dialect, fields int) int {
+	if dialect == db.DialectSQLite {
+		return 999 / fields
+	}
+	return 1000
+}
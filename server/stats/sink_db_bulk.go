@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/titpetric/microservice/db"
+	"github.com/titpetric/microservice/internal"
+)
+
+// BulkDatabaseSink writes rows into IncomingTable with prepared multi-row inserts
+//
+// Unlike DatabaseSink, it avoids reflection and query building for every
+// batch. All batches of a Write are inserted within a single transaction,
+// and statements are prepared on the transaction, once per batch size.
+// Statements aren't cached between writes, as a statement prepared on the
+// pool would be prepared again on the transaction's connection. Partial
+// batches are split into power of two sizes, to keep the number of
+// prepared statements low.
+type BulkDatabaseSink struct {
+	sync.Mutex
+
+	db        *sqlx.DB
+	dialect   db.Dialect
+	batchSize int
+
+	// queries hold insert queries keyed by row count
+	queries map[int]string
+}
+
+// NewBulkDatabaseSink creates a *BulkDatabaseSink
+func NewBulkDatabaseSink(handle *sqlx.DB) *BulkDatabaseSink {
+	dialect := db.DialectFor(handle.DriverName())
+
+	return &BulkDatabaseSink{
+		db:        handle,
+		dialect:   dialect,
+		batchSize: maxBatchRows(dialect, len(IncomingFields)),
+		queries:   make(map[int]string),
+	}
+}
+
+// Write inserts rows in batches within a transaction
+func (sink *BulkDatabaseSink) Write(rows []*Incoming) error {
+	tx, err := sink.db.Beginx()
+	if err != nil {
+		return internal.DBError(errors.WithStack(err))
+	}
+	if err := sink.write(tx, rows); err != nil {
+		tx.Rollback()
+		return internal.DBError(err)
+	}
+	return internal.DBError(errors.WithStack(tx.Commit()))
+}
+
+func (sink *BulkDatabaseSink) write(tx *sqlx.Tx, rows []*Incoming) error {
+	statements := make(map[int]*sqlx.Stmt)
+	defer func() {
+		for _, stmt := range statements {
+			stmt.Close()
+		}
+	}()
+
+	args := make([]interface{}, 0, len(IncomingFields)*sink.batchSize)
+	for _, size := range sink.batches(len(rows)) {
+		stmt, ok := statements[size]
+		if !ok {
+			var err error
+			if stmt, err = tx.Preparex(sink.query(size)); err != nil {
+				return errors.WithStack(err)
+			}
+			statements[size] = stmt
+		}
+
+		args = args[:0]
+		for _, row := range rows[:size] {
+			args = append(args, row.ID, row.Property, row.PropertySection, row.PropertyID, row.RemoteIP, row.Stamp)
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return errors.WithStack(err)
+		}
+		rows = rows[size:]
+	}
+	return nil
+}
+
+// Close is a no-op, the database handle is shared
+func (*BulkDatabaseSink) Close() error {
+	return nil
+}
+
+// batches splits count into full batches, followed by power of two sizes
+func (sink *BulkDatabaseSink) batches(count int) []int {
+	result := []int{}
+	for count > 0 {
+		size := sink.batchSize
+		if count < size {
+			size = 1
+			for size*2 <= count {
+				size *= 2
+			}
+		}
+		result = append(result, size)
+		count -= size
+	}
+	return result
+}
+
+// query returns a cached insert query for `size` rows
+func (sink *BulkDatabaseSink) query(size int) string {
+	sink.Lock()
+	defer sink.Unlock()
+
+	if query, ok := sink.queries[size]; ok {
+		return query
+	}
+
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?,", len(IncomingFields)), ",") + ")"
+	values := strings.TrimSuffix(strings.Repeat(placeholders+",", size), ",")
+	query := fmt.Sprintf("insert into %s (%s) values %s", sink.dialect.Quote(IncomingTable), strings.Join(IncomingFields, ","), values)
+
+	query = sink.db.Rebind(query)
+	sink.queries[size] = query
+	return query
+}
@@ -0,0 +1,95 @@
+// +build cgo
+
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/titpetric/microservice/db"
+)
+
+func newSinkTestDB(tb testing.TB) *sqlx.DB {
+	options := db.ConnectionOptions{}
+	options.Credentials.Driver = "sqlite"
+	options.Credentials.DSN = ":memory:"
+
+	handle, err := db.ConnectWithOptions(context.Background(), options)
+	if err != nil {
+		tb.Fatalf("Unexpected error when connecting: %+v", err)
+	}
+	if err := db.Run("stats", handle); err != nil {
+		tb.Fatalf("Unexpected error when running migrations: %+v", err)
+	}
+	return handle
+}
+
+func newSinkTestRows(offset, count int) []*Incoming {
+	rows := make([]*Incoming, count)
+	for i := range rows {
+		rows[i] = &Incoming{
+			ID:              uint64(offset + i + 1),
+			Property:        "news",
+			PropertySection: 1,
+			PropertyID:      uint32(i),
+			RemoteIP:        "127.0.0.1",
+		}
+		rows[i].SetStamp(time.Now())
+	}
+	return rows
+}
+
+func TestBulkDatabaseSink(t *testing.T) {
+	handle := newSinkTestDB(t)
+	defer handle.Close()
+
+	sink := NewBulkDatabaseSink(handle)
+	defer sink.Close()
+
+	if err := sink.Write(newSinkTestRows(0, 1234)); err != nil {
+		t.Fatalf("Unexpected error on Write: %+v", err)
+	}
+
+	var count int
+	if err := handle.Get(&count, "select count(*) from incoming"); err != nil {
+		t.Fatalf("Unexpected error when counting rows: %+v", err)
+	}
+	if count != 1234 {
+		t.Fatalf("Unexpected row count: %d != 1234", count)
+	}
+}
+
+func benchmarkSink(b *testing.B, newSink func(*sqlx.DB) Sink) {
+	handle := newSinkTestDB(b)
+	defer handle.Close()
+
+	sink := newSink(handle)
+	defer sink.Close()
+
+	batch := 5000
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		rows := newSinkTestRows(i*batch, batch)
+		b.StartTimer()
+		if err := sink.Write(rows); err != nil {
+			b.Fatalf("Unexpected error on Write: %+v", err)
+		}
+	}
+}
+
+func BenchmarkDatabaseSink(b *testing.B) {
+	benchmarkSink(b, func(handle *sqlx.DB) Sink {
+		return NewDatabaseSink(handle)
+	})
+}
+
+func BenchmarkBulkDatabaseSink(b *testing.B) {
+	benchmarkSink(b, func(handle *sqlx.DB) Sink {
+		return NewBulkDatabaseSink(handle)
+	})
+}
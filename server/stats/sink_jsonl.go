@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// JSONLSink writes rows as JSON lines into hourly rotated files
+type JSONLSink struct {
+	sync.Mutex
+
+	path     string
+	filename string
+	file     *os.File
+}
+
+// NewJSONLSink creates a *JSONLSink writing into path
+func NewJSONLSink(path string) (*JSONLSink, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	return &JSONLSink{
+		path: path,
+	}, nil
+}
+
+// Write appends rows to the file for the current hour
+func (sink *JSONLSink) Write(rows []*Incoming) error {
+	sink.Lock()
+	defer sink.Unlock()
+
+	if err := sink.rotate(time.Now()); err != nil {
+		return err
+	}
+	return writeJSONL(sink.file, rows)
+}
+
+// Close closes the currently open file
+func (sink *JSONLSink) Close() error {
+	sink.Lock()
+	defer sink.Unlock()
+
+	if sink.file == nil {
+		return nil
+	}
+	err := sink.file.Close()
+	sink.file, sink.filename = nil, ""
+	return err
+}
+
+// rotate opens a new file when the hour changes
+func (sink *JSONLSink) rotate(now time.Time) error {
+	filename := filepath.Join(sink.path, "incoming-"+now.Format("2006-01-02-15")+".jsonl")
+	if filename == sink.filename {
+		return nil
+	}
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if sink.file != nil {
+		sink.file.Close()
+	}
+	sink.file, sink.filename = file, filename
+	return nil
+}
@@ -0,0 +1,35 @@
+package stats
+
+// TeeSink writes rows to several sinks
+type TeeSink struct {
+	sinks []Sink
+}
+
+// NewTeeSink creates a *TeeSink
+func NewTeeSink(sinks ...Sink) *TeeSink {
+	return &TeeSink{
+		sinks: sinks,
+	}
+}
+
+// Write writes rows to all sinks, returning the first error
+func (sink *TeeSink) Write(rows []*Incoming) error {
+	var result error
+	for _, s := range sink.sinks {
+		if err := s.Write(rows); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// Close closes all sinks, returning the first error
+func (sink *TeeSink) Close() error {
+	var result error
+	for _, s := range sink.sinks {
+		if err := s.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/titpetric/microservice/db"
+)
+
+func TestDatabaseSinkQuery(t *testing.T) {
+	fields := "id,property,property_section,property_id,remote_ip,stamp"
+	named := ":id,:property,:property_section,:property_id,:remote_ip,:stamp"
+	cases := map[string]string{
+		"mysql":    "insert into `incoming` (" + fields + ") values (" + named + ")",
+		"postgres": `insert into "incoming" (` + fields + ") values (" + named + ")",
+		"pgx":      `insert into "incoming" (` + fields + ") values (" + named + ")",
+		"sqlite3":  `insert into "incoming" (` + fields + ") values (" + named + ")",
+	}
+	for driver, expected := range cases {
+		sink := NewDatabaseSink(sqlx.NewDb(nil, driver))
+		if sink.query != expected {
+			t.Errorf("Unexpected query for %s: %s != %s", driver, sink.query, expected)
+		}
+	}
+}
+
+func TestBulkDatabaseSinkQuery(t *testing.T) {
+	fields := "id,property,property_section,property_id,remote_ip,stamp"
+	cases := map[string]string{
+		"mysql":    "insert into `incoming` (" + fields + ") values (?,?,?,?,?,?),(?,?,?,?,?,?)",
+		"postgres": `insert into "incoming" (` + fields + ") values ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12)",
+		"sqlite3":  `insert into "incoming" (` + fields + ") values (?,?,?,?,?,?),(?,?,?,?,?,?)",
+	}
+	for driver, expected := range cases {
+		sink := NewBulkDatabaseSink(sqlx.NewDb(nil, driver))
+		if query := sink.query(2); query != expected {
+			t.Errorf("Unexpected query for %s: %s != %s", driver, query, expected)
+		}
+	}
+}
+
+func TestMaxBatchRows(t *testing.T) {
+	cases := map[string]int{
+		"mysql":    1000,
+		"postgres": 1000,
+		"sqlite3":  166,
+	}
+	for driver, expected := range cases {
+		if rows := maxBatchRows(db.DialectFor(driver), len(IncomingFields)); rows != expected {
+			t.Errorf("Unexpected batch rows for %s: %d != %d", driver, rows, expected)
+		}
+		if size := NewDatabaseSink(sqlx.NewDb(nil, driver)).batchSize; size != expected {
+			t.Errorf("Unexpected database sink batch size for %s: %d != %d", driver, size, expected)
+		}
+		if size := NewBulkDatabaseSink(sqlx.NewDb(nil, driver)).batchSize; size != expected {
+			t.Errorf("Unexpected bulk sink batch size for %s: %d != %d", driver, size, expected)
+		}
+	}
+}
+
+func TestSinkTee(t *testing.T) {
+	assert := func(ok bool, format string, params ...interface{}) {
+		if !ok {
+			t.Fatalf(format, params...)
+		}
+	}
+
+	var a, b bytes.Buffer
+	sink := NewTeeSink(NewWriterSink(&a), NewWriterSink(&b))
+
+	rows := []*Incoming{
+		&Incoming{ID: 1, Property: "news"},
+		&Incoming{ID: 2, Property: "news"},
+	}
+	assert(nil == sink.Write(rows), "Expected no error on sink.Write")
+	assert(nil == sink.Close(), "Expected no error on sink.Close")
+
+	assert(a.String() == b.String(), "Unexpected tee output: %q != %q", a.String(), b.String())
+
+	lines := strings.Split(strings.TrimSpace(a.String()), "\n")
+	assert(len(lines) == 2, "Unexpected line count: %d != 2", len(lines))
+	assert(strings.HasPrefix(lines[0], `{"id":1,"property":"news"`), "Unexpected line: %s", lines[0])
+}
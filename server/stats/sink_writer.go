@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// incomingJSON is the JSON encoding of Incoming{}, one row per line
+type incomingJSON struct {
+	ID              uint64     `json:"id"`
+	Property        string     `json:"property"`
+	PropertySection uint32     `json:"property_section"`
+	PropertyID      uint32     `json:"property_id"`
+	RemoteIP        string     `json:"remote_ip"`
+	Stamp           *time.Time `json:"stamp"`
+}
+
+// WriterSink writes rows as JSON lines into an io.Writer
+type WriterSink struct {
+	sync.Mutex
+	w io.Writer
+}
+
+// NewWriterSink creates a *WriterSink
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{
+		w: w,
+	}
+}
+
+// Write encodes rows as JSON lines
+func (sink *WriterSink) Write(rows []*Incoming) error {
+	sink.Lock()
+	defer sink.Unlock()
+	return writeJSONL(sink.w, rows)
+}
+
+// Close is a no-op, the writer isn't owned by the sink
+func (*WriterSink) Close() error {
+	return nil
+}
+
+func writeJSONL(w io.Writer, rows []*Incoming) error {
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+	for _, row := range rows {
+		if err := encoder.Encode(incomingJSON(*row)); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
@@ -31,7 +31,7 @@ type Incoming struct {
 func (i *Incoming) SetStamp(t time.Time) { i.Stamp = &t }
 
 // IncomingTable is the name of the table in the DB
-const IncomingTable = "`incoming`"
+const IncomingTable = "incoming"
 
 // IncomingFields are all the field names in the DB table
 var IncomingFields = []string{"id", "property", "property_section", "property_id", "remote_ip", "stamp"}
@@ -66,7 +66,7 @@ type IncomingProc struct {
 func (i *IncomingProc) SetStamp(t time.Time) { i.Stamp = &t }
 
 // IncomingProcTable is the name of the table in the DB
-const IncomingProcTable = "`incoming_proc`"
+const IncomingProcTable = "incoming_proc"
 
 // IncomingProcFields are all the field names in the DB table
 var IncomingProcFields = []string{"id", "property", "property_section", "property_id", "remote_ip", "stamp"}
@@ -74,6 +74,44 @@ var IncomingProcFields = []string{"id", "property", "property_section", "propert
 // IncomingProcPrimaryFields are the primary key fields in the DB table
 var IncomingProcPrimaryFields = []string{"id"}
 
+// IngestKeys generated for db table `ingest_keys`
+//
+// Ingestion credentials per property
+type IngestKeys struct {
+	// Key ID, sent with signed requests
+	KeyID string `db:"key_id" json:"-"`
+
+	// Property name the key can push to
+	Property string `db:"property" json:"-"`
+
+	// HMAC secret, empty if signing is disabled
+	Secret string `db:"secret" json:"-"`
+
+	// Hex SHA-256 of the API key, empty if disabled
+	APIKeyHash string `db:"api_key_hash" json:"-"`
+
+	// Key creation time
+	CreatedAt *time.Time `db:"created_at" json:"-"`
+
+	// Key expiry time, NULL for no expiry
+	ExpiresAt *time.Time `db:"expires_at" json:"-"`
+}
+
+// SetCreatedAt sets CreatedAt which requires a *time.Time
+func (i *IngestKeys) SetCreatedAt(t time.Time) { i.CreatedAt = &t }
+
+// SetExpiresAt sets ExpiresAt which requires a *time.Time
+func (i *IngestKeys) SetExpiresAt(t time.Time) { i.ExpiresAt = &t }
+
+// IngestKeysTable is the name of the table in the DB
+const IngestKeysTable = "ingest_keys"
+
+// IngestKeysFields are all the field names in the DB table
+var IngestKeysFields = []string{"key_id", "property", "secret", "api_key_hash", "created_at", "expires_at"}
+
+// IngestKeysPrimaryFields are the primary key fields in the DB table
+var IngestKeysPrimaryFields = []string{"key_id"}
+
 // Migrations generated for db table `migrations`
 type Migrations struct {
 	// Microservice or project name
@@ -90,7 +128,7 @@ type Migrations struct {
 }
 
 // MigrationsTable is the name of the table in the DB
-const MigrationsTable = "`migrations`"
+const MigrationsTable = "migrations"
 
 // MigrationsFields are all the field names in the DB table
 var MigrationsFields = []string{"project", "filename", "statement_index", "status"}
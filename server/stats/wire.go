@@ -7,12 +7,15 @@ import (
 
 	"github.com/google/wire"
 
+	"github.com/titpetric/microservice/config"
 	"github.com/titpetric/microservice/inject"
 )
 
-func New(ctx context.Context) (*Server, error) {
+func New(ctx context.Context, cfg *config.Config) (*Server, error) {
 	wire.Build(
+		NewSink,
 		NewFlusher,
+		NewIngestAuth,
 		inject.Inject,
 		wire.Struct(new(Server), "*"),
 	)
@@ -7,26 +7,42 @@ package stats
 
 import (
 	"context"
+	"github.com/titpetric/microservice/config"
 	"github.com/titpetric/microservice/db"
 	"github.com/titpetric/microservice/inject"
 )
 
 // Injectors from wire.go:
 
-func New(ctx context.Context) (*Server, error) {
-	sqlxDB, err := db.Connect(ctx)
+func New(ctx context.Context, cfg *config.Config) (*Server, error) {
+	credentials := cfg.Database
+	sqlxDB, err := db.Connect(ctx, credentials)
 	if err != nil {
 		return nil, err
 	}
-	sonyflake := inject.Sonyflake()
-	flusher, err := NewFlusher(ctx, sqlxDB)
+	server := cfg.Server
+	sonyflake := inject.Sonyflake(server)
+	log := cfg.Log
+	logger := inject.Logger(log)
+	flusher := cfg.Flusher
+	sink, err := NewSink(sqlxDB, flusher)
 	if err != nil {
 		return nil, err
 	}
-	server := &Server{
-		db:        sqlxDB,
-		sonyflake: sonyflake,
-		flusher:   flusher,
+	statsFlusher, err := NewFlusher(ctx, sink, logger, flusher)
+	if err != nil {
+		return nil, err
+	}
+	ingest := cfg.Ingest
+	ingestAuth := NewIngestAuth(ctx, sqlxDB, logger, ingest)
+	cors := cfg.CORS
+	corsOrigins := inject.CORSOrigins(cors)
+	statsServer := &Server{
+		db:         sqlxDB,
+		sonyflake:  sonyflake,
+		flusher:    statsFlusher,
+		ingestAuth: ingestAuth,
+		origins:    corsOrigins,
 	}
-	return server, nil
+	return statsServer, nil
 }
@@ -6,15 +6,28 @@ package ${SERVICE}
 import (
 	"net/http"
 
+	"${MODULE}/internal"
 	"${MODULE}/rpc/${SERVICE}"
 )
 
 // New creates a ${SERVICE_CAMEL} RPC client
-func New() ${SERVICE}.${SERVICE_CAMEL}Service {
-	return NewCustom("http://${SERVICE}.service:3000", &http.Client{})
+//
+// Endpoints are discovered with internal.NewResolver, falling back
+// to the default service address.
+func New(client *http.Client, discovery internal.Discovery) ${SERVICE}.${SERVICE_CAMEL}Service {
+	return NewCustom("http://${SERVICE}.service:3000", internal.WithHTTPClient(client), internal.WithDiscovery("${SERVICE}", discovery))
 }
 
-// NewCustom creates a ${SERVICE_CAMEL} RPC client with custom Address/HTTP Client
-func NewCustom(addr string, client ${SERVICE}.HTTPClient) ${SERVICE}.${SERVICE_CAMEL}Service {
-	return ${SERVICE}.New${SERVICE_CAMEL}ServiceJSONClient(addr, client)
+// NewCustom creates a ${SERVICE_CAMEL} RPC client with custom Address/client options
+//
+// The client uses protobuf encoding, unless internal.WithJSON is passed.
+// Failed requests are retried with exponential backoff, and a circuit
+// breaker stops requests to an unhealthy service.
+func NewCustom(addr string, options ...internal.ClientOption) ${SERVICE}.${SERVICE_CAMEL}Service {
+	opts := internal.NewClientOptions(options...)
+	client := internal.NewClient(opts)
+	if opts.JSON {
+		return ${SERVICE}.New${SERVICE_CAMEL}ServiceJSONClient(addr, client)
+	}
+	return ${SERVICE}.New${SERVICE_CAMEL}ServiceProtobufClient(addr, client)
 }
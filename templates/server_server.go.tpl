@@ -1,8 +1,13 @@
 package ${SERVICE}
 
 import (
+	"log/slog"
+
+	"net/http"
+
 	"github.com/jmoiron/sqlx"
 
+	"${MODULE}/internal"
 	"${MODULE}/rpc/${SERVICE}"
 )
 
@@ -11,8 +16,18 @@ type Server struct {
 	db *sqlx.DB
 }
 
+// AuthRules declare the authorization for RPC methods
+var AuthRules = internal.AuthRules{
+	"*": {Scopes: []string{"${SERVICE}:admin"}},
+}
+
+// RegisterHandlers mounts HTTP endpoints next to the RPC handlers
+func RegisterHandlers(mux *http.ServeMux, service ${SERVICE}.${SERVICE_CAMEL}Service, log *slog.Logger) {
+}
+
 // Shutdown is a cleanup hook after SIGTERM
-func (*Server) Shutdown() {
+func (svc *Server) Shutdown() {
+	svc.db.Close()
 }
 
 var _ ${SERVICE}.${SERVICE_CAMEL}Service = &Server{}
@@ -7,10 +7,11 @@ import (
 
 	"github.com/google/wire"
 
+	"${MODULE}/config"
 	"${MODULE}/inject"
 )
 
-func New(ctx context.Context) (*Server, error) {
+func New(ctx context.Context, cfg *config.Config) (*Server, error) {
 	wire.Build(
 		inject.Inject,
 		wire.Struct(new(Server), "*"),